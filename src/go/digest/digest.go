@@ -0,0 +1,192 @@
+// Package digest computes and verifies the Content-Digest and Repr-Digest
+// fields of RFC 9530 while bodies stream through, without buffering them.
+//
+// Both fields share one format. Content-Digest covers the message content as
+// sent, Repr-Digest the selected representation (before any content coding);
+// callers feed whichever bytes the field they are producing refers to.
+package digest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"sync"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/header/sfv"
+)
+
+// Algorithm is a digest algorithm from the IANA Hash Algorithms for HTTP
+// Digest Fields registry
+type Algorithm struct {
+	// Name is the registered key, e.g. "sha-256".
+	Name string
+	// New returns a fresh hash.
+	New func() hash.Hash
+}
+
+// Algorithms registered by default
+var (
+	SHA256 = Algorithm{Name: "sha-256", New: sha256.New}
+	SHA512 = Algorithm{Name: "sha-512", New: sha512.New}
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Algorithm{
+		SHA256.Name: SHA256,
+		SHA512.Name: SHA512,
+	}
+)
+
+// Register makes an algorithm available for verification, replacing any
+// algorithm with the same name
+func Register(alg Algorithm) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[alg.Name] = alg
+}
+
+func lookup(name string) (Algorithm, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	alg, ok := registry[name]
+	return alg, ok
+}
+
+// Hasher computes digests with one or more algorithms over everything
+// written to it. Use it with io.TeeReader or io.MultiWriter to hash a body
+// while it is being sent or received.
+type Hasher struct {
+	algs   []Algorithm
+	hashes []hash.Hash
+}
+
+// NewHasher creates a Hasher for the given algorithms, defaulting to SHA256
+func NewHasher(algs ...Algorithm) *Hasher {
+	if len(algs) == 0 {
+		algs = []Algorithm{SHA256}
+	}
+	h := &Hasher{algs: algs, hashes: make([]hash.Hash, len(algs))}
+	for i, alg := range algs {
+		h.hashes[i] = alg.New()
+	}
+	return h
+}
+
+// Write adds p to every digest
+func (h *Hasher) Write(p []byte) (int, error) {
+	for _, hh := range h.hashes {
+		hh.Write(p)
+	}
+	return len(p), nil
+}
+
+// Sums returns the current digests by algorithm name
+func (h *Hasher) Sums() map[string][]byte {
+	sums := make(map[string][]byte, len(h.algs))
+	for i, alg := range h.algs {
+		sums[alg.Name] = h.hashes[i].Sum(nil)
+	}
+	return sums
+}
+
+// Value returns the field value for the data written so far, e.g.
+//
+//	sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:
+func (h *Hasher) Value() (string, error) {
+	dict := make(sfv.Dictionary, len(h.algs))
+	for i, alg := range h.algs {
+		dict[i] = sfv.DictMember{Key: alg.Name, Member: sfv.Item{Value: h.hashes[i].Sum(nil)}}
+	}
+	return sfv.SerializeDictionary(dict)
+}
+
+// Parse parses a Content-Digest or Repr-Digest field value into digests by
+// algorithm name. Members that are not byte sequences are ignored.
+func Parse(value string) (map[string][]byte, error) {
+	dict, err := sfv.ParseDictionary(value)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string][]byte, len(dict))
+	for _, m := range dict {
+		if item, ok := m.Member.(sfv.Item); ok {
+			if sum, ok := item.Value.([]byte); ok {
+				sums[m.Key] = sum
+			}
+		}
+	}
+	return sums, nil
+}
+
+// Reader verifies a body against a digest field as it is read. It hashes the
+// data on the fly and, instead of io.EOF, returns an IntegrityFailure error
+// if any supported digest does not match.
+type Reader struct {
+	r        io.Reader
+	hasher   *Hasher
+	expected map[string][]byte
+	err      error
+}
+
+// NewReader wraps body for verification against a Content-Digest or
+// Repr-Digest field value. Unknown algorithms in the field are ignored, as
+// RFC 9530 allows, but a field with no registered algorithm at all fails with
+// IntegrityFailure since the body could not be verified.
+func NewReader(body io.Reader, fieldValue string) (*Reader, error) {
+	sums, err := Parse(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		if _, ok := lookup(name); ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, httperrors.NewHttpError(httperrors.IntegrityFailure,
+			fmt.Errorf("digest: no supported algorithm in %q", fieldValue))
+	}
+	sort.Strings(names)
+
+	algs := make([]Algorithm, len(names))
+	expected := make(map[string][]byte, len(names))
+	for i, name := range names {
+		algs[i], _ = lookup(name)
+		expected[name] = sums[name]
+	}
+	return &Reader{r: body, hasher: NewHasher(algs...), expected: expected}, nil
+}
+
+// Read reads from the body, checking the digests once it is exhausted
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.r.Read(p)
+	r.hasher.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		err = r.verify()
+		r.err = err
+	}
+	return n, err
+}
+
+func (r *Reader) verify() error {
+	for name, got := range r.hasher.Sums() {
+		if !bytes.Equal(got, r.expected[name]) {
+			return httperrors.NewHttpError(httperrors.IntegrityFailure,
+				fmt.Errorf("digest: %s mismatch", name))
+		}
+	}
+	return io.EOF
+}
@@ -0,0 +1,126 @@
+package digest
+
+import (
+	"crypto/md5"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// Example content and digests from RFC 9530 appendix D
+const (
+	exampleContent = `{"hello": "world"}`
+	exampleSHA256  = "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"
+	exampleSHA512  = "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:"
+)
+
+func expectIntegrityFailure(t *testing.T, err error) {
+	t.Helper()
+
+	var httpErr *httperrors.Error
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected *httperrors.Error, got %v", err)
+	}
+	if httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.IntegrityFailure {
+		t.Errorf("Expected IntegrityFailure, got %v", err)
+	}
+}
+
+func TestHasher_Value(t *testing.T) {
+	h := NewHasher(SHA256, SHA512)
+	// Hash in pieces, as a streaming body would arrive
+	io.Copy(h, io.LimitReader(strings.NewReader(exampleContent), 5))
+	io.WriteString(h, exampleContent[5:])
+
+	got, err := h.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if want := exampleSHA256 + ", " + exampleSHA512; got != want {
+		t.Errorf("Value = %q, want %q", got, want)
+	}
+}
+
+func TestHasher_DefaultsToSHA256(t *testing.T) {
+	h := NewHasher()
+	io.WriteString(h, exampleContent)
+	got, err := h.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+	if got != exampleSHA256 {
+		t.Errorf("Value = %q, want %q", got, exampleSHA256)
+	}
+}
+
+func TestReader_Match(t *testing.T) {
+	r, err := NewReader(strings.NewReader(exampleContent), exampleSHA512+", "+exampleSHA256)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(data) != exampleContent {
+		t.Errorf("Unexpected body %q", data)
+	}
+}
+
+func TestReader_Mismatch(t *testing.T) {
+	r, err := NewReader(strings.NewReader(`{"hello": "there"}`), exampleSHA256)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	_, err = io.ReadAll(r)
+	expectIntegrityFailure(t, err)
+
+	// The failure is sticky
+	_, err = r.Read(make([]byte, 1))
+	expectIntegrityFailure(t, err)
+}
+
+func TestReader_IgnoresUnknownAlgorithms(t *testing.T) {
+	r, err := NewReader(strings.NewReader(exampleContent), "unixsum=:AAAA:, "+exampleSHA256)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	if _, err := io.ReadAll(r); err != nil {
+		t.Errorf("Expected unknown algorithm to be ignored, got %v", err)
+	}
+}
+
+func TestNewReader_NoSupportedAlgorithm(t *testing.T) {
+	_, err := NewReader(strings.NewReader(exampleContent), "unixsum=:AAAA:")
+	expectIntegrityFailure(t, err)
+}
+
+func TestNewReader_MalformedField(t *testing.T) {
+	_, err := NewReader(strings.NewReader(exampleContent), "sha-256=not-bytes=")
+	if err == nil {
+		t.Fatal("Expected a parse error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// md5 is deprecated for digest fields, but is a convenient custom algorithm here
+	Register(Algorithm{Name: "x-test-md5", New: md5.New})
+
+	h := NewHasher(Algorithm{Name: "x-test-md5", New: md5.New})
+	io.WriteString(h, exampleContent)
+	value, err := h.Value()
+	if err != nil {
+		t.Fatalf("Value failed: %v", err)
+	}
+
+	r, err := NewReader(strings.NewReader(exampleContent), value)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	if _, err := io.ReadAll(r); err != nil {
+		t.Errorf("Expected registered algorithm to verify, got %v", err)
+	}
+}
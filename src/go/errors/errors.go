@@ -14,6 +14,11 @@ const (
 	ConnectionClosed
 	SocketCloseFailure
 	InitFailure
+	LocalAddrUnavailable
+	TlsHandshakeFailure
+	TlsCertificateFailure
+	Cancelled
+	Timeout
 )
 
 func (e TransportError) Error() string {
@@ -34,6 +39,16 @@ func (e TransportError) Error() string {
 		return "Socket close failed"
 	case InitFailure:
 		return "Initialization failed"
+	case LocalAddrUnavailable:
+		return "Local address unavailable"
+	case TlsHandshakeFailure:
+		return "TLS handshake failed"
+	case TlsCertificateFailure:
+		return "TLS certificate verification failed"
+	case Cancelled:
+		return "Operation cancelled"
+	case Timeout:
+		return "Operation timed out"
 	default:
 		return fmt.Sprintf("Unknown transport error: %d", e)
 	}
@@ -47,6 +62,9 @@ const (
 	HttpParseFailure
 	InvalidRequest
 	HttpInitFailure
+	NotAcceptable
+	IntegrityFailure
+	PreconditionFailed
 )
 
 func (e HttpClientError) Error() string {
@@ -59,6 +77,12 @@ func (e HttpClientError) Error() string {
 		return "Invalid HTTP request"
 	case HttpInitFailure:
 		return "HTTP client initialization failed"
+	case NotAcceptable:
+		return "Response content not acceptable"
+	case IntegrityFailure:
+		return "Content integrity check failed"
+	case PreconditionFailed:
+		return "Precondition failed"
 	default:
 		return fmt.Sprintf("Unknown HTTP client error: %d", e)
 	}
@@ -0,0 +1,126 @@
+package header
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// maxFilenameLength bounds sanitized filenames to what common filesystems accept
+const maxFilenameLength = 255
+
+// ContentDisposition is a parsed Content-Disposition header (RFC 6266)
+type ContentDisposition struct {
+	// Type is the lowercased disposition type, e.g. "attachment" or "inline".
+	Type string
+	// Params holds the disposition parameters by lowercased name.
+	// Extended parameters such as filename* (RFC 5987) are decoded and stored
+	// under their plain name, taking precedence over the plain form.
+	Params map[string]string
+}
+
+// ParseContentDisposition parses a Content-Disposition field value
+func ParseContentDisposition(value string) (ContentDisposition, error) {
+	dispType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return ContentDisposition{}, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	return ContentDisposition{Type: dispType, Params: params}, nil
+}
+
+// IsAttachment reports whether the recipient is expected to save the content
+func (cd ContentDisposition) IsAttachment() bool {
+	return cd.Type == "attachment"
+}
+
+// Filename returns the raw, unsanitized filename parameter.
+// Never use it as a path without passing it through SanitizeFilename first.
+func (cd ContentDisposition) Filename() string {
+	return cd.Params["filename"]
+}
+
+// SuggestedFilename returns a sanitized filename that is safe to create in
+// a local directory, or fallback if the header does not provide a usable one.
+func (cd ContentDisposition) SuggestedFilename(fallback string) string {
+	if name := SanitizeFilename(cd.Filename()); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// SanitizeFilename reduces an untrusted filename to a single safe path element.
+// Directory components (using either separator), control characters and
+// characters reserved on common filesystems are removed, and Windows device
+// names such as CON or LPT1.log are prefixed with an underscore. Long names
+// are shortened before the extension. An empty string is returned if nothing
+// usable remains.
+func SanitizeFilename(name string) string {
+	// Both separators are stripped so that "..\..\evil.exe" is just as harmless
+	// on Windows as "../../evil" is on Unix
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == utf8.RuneError, unicode.IsControl(r):
+			continue
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	// Leading dots would create hidden files (or "." and ".."), trailing
+	// dots and spaces are silently dropped by Windows
+	cleaned := strings.TrimLeft(strings.TrimRight(b.String(), ". "), ". ")
+	cleaned = strings.TrimSpace(cleaned)
+
+	// Checked after truncation, which can shorten the stem to a reserved name
+	cleaned = truncateFilename(cleaned)
+	if isWindowsDeviceName(cleaned) {
+		cleaned = truncateFilename("_" + cleaned)
+	}
+	return cleaned
+}
+
+// truncateFilename shortens name to maxFilenameLength bytes by cutting the
+// stem, so the extension survives
+func truncateFilename(name string) string {
+	if len(name) <= maxFilenameLength {
+		return name
+	}
+	ext := path.Ext(name)
+	if len(ext) > maxFilenameLength/2 {
+		// An extension this long is more likely part of the name
+		ext = ""
+	}
+
+	stem := name[:len(name)-len(ext)]
+	for len(stem)+len(ext) > maxFilenameLength {
+		// Trim whole runes so the result stays valid UTF-8
+		_, size := utf8.DecodeLastRuneInString(stem)
+		stem = stem[:len(stem)-size]
+	}
+	// The cut can expose dots or spaces that Windows would drop
+	return strings.TrimRight(stem, ". ") + ext
+}
+
+// isWindowsDeviceName reports whether name opens a device on Windows. The
+// reserved names match case-insensitively and regardless of any extension.
+func isWindowsDeviceName(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	base = strings.ToUpper(strings.TrimRight(base, " "))
+	switch base {
+	case "CON", "PRN", "AUX", "NUL":
+		return true
+	}
+	if len(base) == 4 && (strings.HasPrefix(base, "COM") || strings.HasPrefix(base, "LPT")) {
+		return base[3] >= '1' && base[3] <= '9'
+	}
+	return false
+}
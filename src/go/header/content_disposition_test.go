@@ -0,0 +1,125 @@
+package header
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestParseContentDisposition_Attachment(t *testing.T) {
+	cd, err := ParseContentDisposition(`Attachment; filename="report.pdf"`)
+	if err != nil {
+		t.Fatalf("ParseContentDisposition failed: %v", err)
+	}
+	if !cd.IsAttachment() {
+		t.Errorf("Expected attachment, got %q", cd.Type)
+	}
+	if cd.Filename() != "report.pdf" {
+		t.Errorf("Expected report.pdf, got %q", cd.Filename())
+	}
+}
+
+func TestParseContentDisposition_ExtendedFilenameWins(t *testing.T) {
+	cd, err := ParseContentDisposition(`attachment; filename="EURO rates.txt"; filename*=UTF-8''%e2%82%ac%20rates.txt`)
+	if err != nil {
+		t.Fatalf("ParseContentDisposition failed: %v", err)
+	}
+	if cd.Filename() != "€ rates.txt" {
+		t.Errorf("Expected filename* to win, got %q", cd.Filename())
+	}
+}
+
+func TestParseContentDisposition_Malformed(t *testing.T) {
+	_, err := ParseContentDisposition(`attachment; filename=`)
+	if err == nil {
+		t.Fatal("Expected error for malformed header")
+	}
+
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok {
+		t.Fatalf("Expected *httperrors.Error, got %T", err)
+	}
+	if httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.HttpParseFailure {
+		t.Errorf("Expected HttpParseFailure, got %v", err)
+	}
+}
+
+func TestSuggestedFilename_Fallback(t *testing.T) {
+	cd, err := ParseContentDisposition(`inline`)
+	if err != nil {
+		t.Fatalf("ParseContentDisposition failed: %v", err)
+	}
+	if got := cd.SuggestedFilename("download"); got != "download" {
+		t.Errorf("Expected fallback, got %q", got)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`..\..\windows\evil.exe`, "evil.exe"},
+		{"/absolute/path.txt", "path.txt"},
+		{"..", ""},
+		{".", ""},
+		{"", ""},
+		{".bashrc", "bashrc"},
+		{"trailing. . ", "trailing"},
+		{"a<b>c:d\"e|f?g*h", "a_b_c_d_e_f_g_h"},
+		{"tab\there\x00.txt", "tabhere.txt"},
+		{"€ rates.txt", "€ rates.txt"},
+		{"CON", "_CON"},
+		{"NUL.txt", "_NUL.txt"},
+		{"COM1", "_COM1"},
+		{"LPT1.log", "_LPT1.log"},
+		{"aux.tar.gz", "_aux.tar.gz"},
+		{"com0.txt", "com0.txt"},
+		{"console.txt", "console.txt"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeFilename(tt.in); got != tt.want {
+			t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeFilename_Length(t *testing.T) {
+	got := SanitizeFilename(strings.Repeat("é", 200))
+	if len(got) > maxFilenameLength {
+		t.Errorf("Expected at most %d bytes, got %d", maxFilenameLength, len(got))
+	}
+	if !strings.HasPrefix(strings.Repeat("é", 200), got) {
+		t.Errorf("Expected truncation on a rune boundary, got %q", got)
+	}
+}
+
+func TestSanitizeFilename_LengthKeepsExtension(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"ascii", strings.Repeat("a", 300) + ".pdf", strings.Repeat("a", 251) + ".pdf"},
+		{"multibyte stem", strings.Repeat("é", 200) + ".txt", strings.Repeat("é", 125) + ".txt"},
+		{"cut exposes dots", strings.Repeat("a", 250) + "....." + strings.Repeat("b", 10) + ".txt", strings.Repeat("a", 250) + ".txt"},
+		{"cut exposes reserved name", "COM1" + strings.Repeat(" ", 260) + "x.txt", "_COM1.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeFilename(tt.input)
+			if got != tt.want {
+				t.Errorf("SanitizeFilename(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if len(got) > maxFilenameLength || !utf8.ValidString(got) {
+				t.Errorf("Expected at most %d bytes of valid UTF-8, got %d", maxFilenameLength, len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package header
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// ContentRange is a parsed Content-Range header (RFC 9110 section 14.4)
+type ContentRange struct {
+	// Unit is the range unit, normally "bytes".
+	Unit string
+	// First and Last are the inclusive positions of the enclosed range.
+	// Both are -1 for an unsatisfied-range ("bytes */1234").
+	First int64
+	Last  int64
+	// Complete is the full representation length, or -1 if unknown ("*").
+	Complete int64
+}
+
+// Length returns the number of bytes enclosed, or 0 for an unsatisfied-range
+func (cr ContentRange) Length() int64 {
+	if cr.First < 0 {
+		return 0
+	}
+	return cr.Last - cr.First + 1
+}
+
+// ParseContentRange parses a Content-Range field value
+func ParseContentRange(value string) (ContentRange, error) {
+	cr, err := parseContentRange(strings.TrimSpace(value))
+	if err != nil {
+		return ContentRange{}, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	return cr, nil
+}
+
+func parseContentRange(value string) (ContentRange, error) {
+	unit, rest, ok := strings.Cut(value, " ")
+	if !ok || unit == "" {
+		return ContentRange{}, fmt.Errorf("content-range: missing unit in %q", value)
+	}
+	rng, complete, ok := strings.Cut(strings.TrimLeft(rest, " "), "/")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("content-range: missing complete length in %q", value)
+	}
+
+	cr := ContentRange{Unit: unit, First: -1, Last: -1, Complete: -1}
+	if complete != "*" {
+		n, err := parseRangeInt(complete)
+		if err != nil {
+			return ContentRange{}, err
+		}
+		cr.Complete = n
+	}
+
+	if rng == "*" {
+		if cr.Complete < 0 {
+			return ContentRange{}, fmt.Errorf("content-range: unsatisfied range needs a complete length")
+		}
+		return cr, nil
+	}
+
+	first, last, ok := strings.Cut(rng, "-")
+	if !ok {
+		return ContentRange{}, fmt.Errorf("content-range: invalid range %q", rng)
+	}
+	var err error
+	if cr.First, err = parseRangeInt(first); err != nil {
+		return ContentRange{}, err
+	}
+	if cr.Last, err = parseRangeInt(last); err != nil {
+		return ContentRange{}, err
+	}
+	if cr.Last < cr.First {
+		return ContentRange{}, fmt.Errorf("content-range: last position before first in %q", rng)
+	}
+	if cr.Complete >= 0 && cr.Last >= cr.Complete {
+		return ContentRange{}, fmt.Errorf("content-range: range %q exceeds complete length", rng)
+	}
+	return cr, nil
+}
+
+// parseRangeInt accepts only plain digits, rejecting signs and whitespace
+// that strconv would otherwise tolerate
+func parseRangeInt(s string) (int64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("content-range: invalid position %q", s)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("content-range: position %q out of range", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,55 @@
+package header
+
+import "testing"
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ContentRange
+	}{
+		{"bytes 0-499/1234", ContentRange{Unit: "bytes", First: 0, Last: 499, Complete: 1234}},
+		{"bytes 500-999/*", ContentRange{Unit: "bytes", First: 500, Last: 999, Complete: -1}},
+		{"bytes */1234", ContentRange{Unit: "bytes", First: -1, Last: -1, Complete: 1234}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseContentRange(tt.in)
+		if err != nil {
+			t.Errorf("ParseContentRange(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseContentRange(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContentRange_Length(t *testing.T) {
+	cr, _ := ParseContentRange("bytes 10-19/100")
+	if cr.Length() != 10 {
+		t.Errorf("Expected length 10, got %d", cr.Length())
+	}
+	cr, _ = ParseContentRange("bytes */100")
+	if cr.Length() != 0 {
+		t.Errorf("Expected length 0 for unsatisfied range, got %d", cr.Length())
+	}
+}
+
+func TestParseContentRange_Malformed(t *testing.T) {
+	tests := []string{
+		"",
+		"bytes",
+		"bytes 0-499",
+		"bytes 499-0/1000",
+		"bytes 0-1000/1000",
+		"bytes -1-5/10",
+		"bytes +1-5/10",
+		"bytes */*",
+		"bytes 0-99999999999999999999/*",
+	}
+	for _, in := range tests {
+		if _, err := ParseContentRange(in); err == nil {
+			t.Errorf("Expected ParseContentRange(%q) to fail", in)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package header
+
+import (
+	"fmt"
+	"math"
+	"mime"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// MediaType is a parsed Content-Type value or media range
+type MediaType struct {
+	// Type and Subtype are lowercased; either may be "*" in a media range.
+	Type    string
+	Subtype string
+	// Params holds the parameters by lowercased name, e.g. "charset".
+	Params map[string]string
+}
+
+// ParseMediaType parses a Content-Type field value such as
+// "application/json; charset=utf-8"
+func ParseMediaType(value string) (MediaType, error) {
+	full, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return MediaType{}, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	typ, subtype, ok := strings.Cut(full, "/")
+	if !ok || typ == "" || subtype == "" {
+		return MediaType{}, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("media type %q has no subtype", full))
+	}
+	return MediaType{Type: typ, Subtype: subtype, Params: params}, nil
+}
+
+// Essence returns "type/subtype" without parameters
+func (m MediaType) Essence() string {
+	return m.Type + "/" + m.Subtype
+}
+
+// Charset returns the charset parameter, lowercased, or "" if absent
+func (m MediaType) Charset() string {
+	return strings.ToLower(m.Params["charset"])
+}
+
+// matchedBy reports whether the media range r covers m, and how specific the
+// match is (0 for */*, 1 for type/*, 2 for an exact type)
+func (m MediaType) matchedBy(r MediaType) (bool, int) {
+	switch {
+	case r.Type == "*" && r.Subtype == "*":
+		return true, 0
+	case r.Type == m.Type && r.Subtype == "*":
+		return true, 1
+	case r.Type == m.Type && r.Subtype == m.Subtype:
+		return true, 2
+	}
+	return false, 0
+}
+
+// Accept builds an Accept header with quality values and checks whether
+// responses satisfy it. Ranges are listed in the order they were added.
+type Accept struct {
+	ranges []acceptRange
+}
+
+type acceptRange struct {
+	media MediaType
+	q     float64
+}
+
+// Add appends a media range such as "application/json" or "text/*" with
+// quality q, which is clamped to [0, 1]. Invalid ranges are reported as
+// InvalidRequest.
+func (a *Accept) Add(mediaRange string, q float64) error {
+	media, err := ParseMediaType(mediaRange)
+	if err != nil {
+		return httperrors.NewHttpError(httperrors.InvalidRequest, err)
+	}
+	a.ranges = append(a.ranges, acceptRange{media: media, q: min(max(q, 0), 1)})
+	return nil
+}
+
+// String renders the Accept field value, omitting q for q=1 and rounding
+// other values to the three decimal places RFC 9110 allows
+func (a *Accept) String() string {
+	parts := make([]string, 0, len(a.ranges))
+	for _, r := range a.ranges {
+		s := r.media.Essence()
+		if q := math.Round(r.q*1000) / 1000; q < 1 {
+			s += ";q=" + strconv.FormatFloat(q, 'f', -1, 64)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Quality returns the quality the Accept header assigns to a content type,
+// using the most specific matching range. An empty Accept accepts anything.
+func (a *Accept) Quality(media MediaType) float64 {
+	if len(a.ranges) == 0 {
+		return 1
+	}
+	best, q := -1, 0.0
+	for _, r := range a.ranges {
+		if ok, specificity := media.matchedBy(r.media); ok && specificity > best {
+			best, q = specificity, r.q
+		}
+	}
+	return q
+}
+
+// Check validates a response against the Accept header. A 406 status or a
+// Content-Type with zero quality yields a NotAcceptable error; a response
+// without Content-Type is accepted.
+func (a *Accept) Check(status int, contentType string) error {
+	if status == 406 {
+		return httperrors.NewHttpError(httperrors.NotAcceptable,
+			fmt.Errorf("server returned 406 for Accept: %s", a.String()))
+	}
+	if contentType == "" {
+		return nil
+	}
+	media, err := ParseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	if a.Quality(media) <= 0 {
+		return httperrors.NewHttpError(httperrors.NotAcceptable,
+			fmt.Errorf("content type %s does not match Accept: %s", media.Essence(), a.String()))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package header
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestParseMediaType(t *testing.T) {
+	m, err := ParseMediaType(`Application/JSON; Charset="UTF-8"`)
+	if err != nil {
+		t.Fatalf("ParseMediaType failed: %v", err)
+	}
+	if m.Type != "application" || m.Subtype != "json" {
+		t.Errorf("Unexpected media type %s", m.Essence())
+	}
+	if m.Charset() != "utf-8" {
+		t.Errorf("Expected charset utf-8, got %q", m.Charset())
+	}
+}
+
+func TestParseMediaType_Malformed(t *testing.T) {
+	for _, in := range []string{"", "json", "application/", "/json", "text/plain; charset"} {
+		if _, err := ParseMediaType(in); err == nil {
+			t.Errorf("Expected ParseMediaType(%q) to fail", in)
+		}
+	}
+}
+
+func TestAccept_String(t *testing.T) {
+	var accept Accept
+	accept.Add("application/json", 1)
+	accept.Add("text/*", 0.5)
+	accept.Add("*/*", 0.12345)
+
+	want := "application/json, text/*;q=0.5, */*;q=0.123"
+	if got := accept.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAccept_Add_Invalid(t *testing.T) {
+	var accept Accept
+	err := accept.Add("json", 1)
+	if err == nil {
+		t.Fatal("Expected error for invalid media range")
+	}
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.InvalidRequest {
+		t.Errorf("Expected InvalidRequest, got %v", err)
+	}
+}
+
+func TestAccept_Quality_MostSpecificWins(t *testing.T) {
+	var accept Accept
+	accept.Add("text/*", 0.5)
+	accept.Add("text/html", 0)
+	accept.Add("*/*", 0.1)
+
+	tests := map[string]float64{
+		"text/html":       0,
+		"text/plain":      0.5,
+		"image/png":       0.1,
+		"TEXT/PLAIN; a=b": 0.5,
+	}
+	for ct, want := range tests {
+		m, err := ParseMediaType(ct)
+		if err != nil {
+			t.Fatalf("ParseMediaType(%q) failed: %v", ct, err)
+		}
+		if got := accept.Quality(m); got != want {
+			t.Errorf("Quality(%q) = %v, want %v", ct, got, want)
+		}
+	}
+}
+
+func TestAccept_Check(t *testing.T) {
+	var accept Accept
+	accept.Add("application/json", 1)
+
+	if err := accept.Check(200, "application/json; charset=utf-8"); err != nil {
+		t.Errorf("Expected JSON response to be acceptable, got %v", err)
+	}
+	if err := accept.Check(204, ""); err != nil {
+		t.Errorf("Expected response without Content-Type to be accepted, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		status      int
+		contentType string
+	}{
+		{406, "text/html"},
+		{200, "text/html"},
+	} {
+		err := accept.Check(tc.status, tc.contentType)
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.NotAcceptable {
+			t.Errorf("Check(%d, %q): expected NotAcceptable, got %v", tc.status, tc.contentType, err)
+		}
+	}
+}
+
+func TestAccept_Empty(t *testing.T) {
+	var accept Accept
+	if err := accept.Check(200, "image/png"); err != nil {
+		t.Errorf("Expected empty Accept to accept anything, got %v", err)
+	}
+}
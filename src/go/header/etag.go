@@ -0,0 +1,77 @@
+package header
+
+import (
+	"fmt"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// ETag is an entity tag (RFC 9110 section 8.8.3)
+type ETag struct {
+	// Tag is the opaque value without quotes.
+	Tag string
+	// Weak is set for W/ tags, which only support weak comparison.
+	Weak bool
+}
+
+// ParseETag parses an ETag field value such as "xyzzy" or W/"xyzzy"
+func ParseETag(value string) (ETag, error) {
+	value = strings.TrimSpace(value)
+	var etag ETag
+	if strings.HasPrefix(value, "W/") {
+		etag.Weak = true
+		value = value[2:]
+	}
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return ETag{}, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("invalid entity tag %q", value))
+	}
+	etag.Tag = value[1 : len(value)-1]
+	for i := 0; i < len(etag.Tag); i++ {
+		// etagc = %x21 / %x23-7E / obs-text
+		if c := etag.Tag[i]; c < 0x21 || c == '"' || c == 0x7f {
+			return ETag{}, httperrors.NewHttpError(httperrors.HttpParseFailure,
+				fmt.Errorf("invalid character %q in entity tag", c))
+		}
+	}
+	return etag, nil
+}
+
+// String formats the entity tag for use in a header
+func (e ETag) String() string {
+	if e.Weak {
+		return `W/"` + e.Tag + `"`
+	}
+	return `"` + e.Tag + `"`
+}
+
+// IfMatch builds an If-Match value that makes an update conditional on the
+// resource still having one of the given entity tags, typically the ETag of
+// a previous GET. If-Match uses strong comparison, so a weak tag could never
+// match and is rejected with InvalidRequest.
+func IfMatch(tags ...ETag) (string, error) {
+	if len(tags) == 0 {
+		return "", httperrors.NewHttpError(httperrors.InvalidRequest,
+			fmt.Errorf("If-Match needs at least one entity tag"))
+	}
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		if tag.Weak {
+			return "", httperrors.NewHttpError(httperrors.InvalidRequest,
+				fmt.Errorf("weak entity tag %s cannot be used in If-Match", tag))
+		}
+		parts[i] = tag.String()
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// CheckPrecondition turns a 412 response to a conditional request into a
+// PreconditionFailed error, meaning the resource was modified concurrently
+func CheckPrecondition(status int) error {
+	if status == 412 {
+		return httperrors.NewHttpError(httperrors.PreconditionFailed,
+			fmt.Errorf("server returned 412; the resource changed since it was fetched"))
+	}
+	return nil
+}
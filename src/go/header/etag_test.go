@@ -0,0 +1,70 @@
+package header
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ETag
+	}{
+		{`"xyzzy"`, ETag{Tag: "xyzzy"}},
+		{` W/"xyzzy" `, ETag{Tag: "xyzzy", Weak: true}},
+		{`""`, ETag{Tag: ""}},
+		{`"33a64df551425fcc55e4d42a148795d9f25f89d4"`, ETag{Tag: "33a64df551425fcc55e4d42a148795d9f25f89d4"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseETag(tt.in)
+		if err != nil {
+			t.Errorf("ParseETag(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseETag(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseETag_Invalid(t *testing.T) {
+	for _, in := range []string{"", "xyzzy", `"xy"zzy"`, `"xy zzy"`, `w/"xyzzy"`} {
+		_, err := ParseETag(in)
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.HttpParseFailure {
+			t.Errorf("Expected HttpParseFailure for %q, got %v", in, err)
+		}
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	got, err := IfMatch(ETag{Tag: "v1"}, ETag{Tag: "v2"})
+	if err != nil {
+		t.Fatalf("IfMatch failed: %v", err)
+	}
+	if got != `"v1", "v2"` {
+		t.Errorf("Unexpected If-Match %q", got)
+	}
+}
+
+func TestIfMatch_RejectsWeakAndEmpty(t *testing.T) {
+	for _, tags := range [][]ETag{nil, {{Tag: "v1", Weak: true}}} {
+		_, err := IfMatch(tags...)
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.InvalidRequest {
+			t.Errorf("Expected InvalidRequest for %v, got %v", tags, err)
+		}
+	}
+}
+
+func TestCheckPrecondition(t *testing.T) {
+	if err := CheckPrecondition(200); err != nil {
+		t.Errorf("Expected no error for 200, got %v", err)
+	}
+	err := CheckPrecondition(412)
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.PreconditionFailed {
+		t.Errorf("Expected PreconditionFailed, got %v", err)
+	}
+}
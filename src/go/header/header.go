@@ -0,0 +1,18 @@
+// Package header provides parsers for HTTP header field values.
+//
+// The parsers operate on raw field values so they can be shared by the
+// protocol and client layers without depending on either.
+package header
+
+// isTokenChar reports whether c is a tchar as defined in RFC 9110 section 5.6.2
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return false
+}
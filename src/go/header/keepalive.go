@@ -0,0 +1,80 @@
+package header
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keepAliveMargin is subtracted from a server's advertised idle timeout so
+// a connection is retired slightly before the server is expected to close it,
+// rather than racing the server's close with a new request. It is capped at
+// half the timeout so that short timeouts such as timeout=1 still allow reuse.
+const keepAliveMargin = time.Second
+
+// maxKeepAliveSeconds is the largest timeout that fits in a time.Duration
+const maxKeepAliveSeconds = math.MaxInt64 / int64(time.Second)
+
+// KeepAlive holds the parameters of a Keep-Alive response header, e.g.
+//
+//	Keep-Alive: timeout=5, max=1000
+type KeepAlive struct {
+	// Timeout is how long the server keeps an idle connection open, or -1 if
+	// not advertised. An advertised timeout of 0 means the connection must
+	// not be reused.
+	Timeout time.Duration
+	// Max is how many more requests the server will accept on the connection, or -1 if not advertised.
+	Max int
+}
+
+// ParseKeepAlive parses one or more Keep-Alive header field values.
+// Parameter names are case-insensitive, values may be quoted, and unknown
+// or malformed parameters are ignored. The second result is false if no
+// usable timeout or max parameter was found. Timeouts too large for a
+// time.Duration are clamped.
+func ParseKeepAlive(values ...string) (KeepAlive, bool) {
+	ka := KeepAlive{Timeout: -1, Max: -1}
+	found := false
+	for _, v := range values {
+		for _, param := range strings.Split(v, ",") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok {
+				continue
+			}
+			name = strings.ToLower(strings.TrimSpace(name))
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				continue
+			}
+			switch name {
+			case "timeout":
+				if ka.Timeout < 0 {
+					ka.Timeout = time.Duration(min(int64(n), maxKeepAliveSeconds)) * time.Second
+					found = true
+				}
+			case "max":
+				if ka.Max < 0 {
+					ka.Max = n
+					found = true
+				}
+			}
+		}
+	}
+	return ka, found
+}
+
+// Reusable reports whether a connection that has been idle for idle, and on
+// which the server advertised k with its last response, should be used for
+// another request. A max or timeout of 0 means the server will not accept
+// another one.
+func (k KeepAlive) Reusable(idle time.Duration) bool {
+	if k.Max == 0 || k.Timeout == 0 {
+		return false
+	}
+	if k.Timeout < 0 {
+		return true
+	}
+	return idle < k.Timeout-min(keepAliveMargin, k.Timeout/2)
+}
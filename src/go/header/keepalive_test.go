@@ -0,0 +1,72 @@
+package header
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseKeepAlive(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   KeepAlive
+		wantOK bool
+	}{
+		{[]string{"timeout=5, max=1000"}, KeepAlive{Timeout: 5 * time.Second, Max: 1000}, true},
+		{[]string{"Timeout=15"}, KeepAlive{Timeout: 15 * time.Second, Max: -1}, true},
+		{[]string{`max="3"`}, KeepAlive{Timeout: -1, Max: 3}, true},
+		{[]string{"timeout=0"}, KeepAlive{Timeout: 0, Max: -1}, true},
+		{[]string{"timeout=1"}, KeepAlive{Timeout: time.Second, Max: -1}, true},
+		{[]string{"timeout=99999999999999"}, KeepAlive{Timeout: time.Duration(maxKeepAliveSeconds) * time.Second, Max: -1}, true},
+		{[]string{"timeout=5", "timeout=60, max=10"}, KeepAlive{Timeout: 5 * time.Second, Max: 10}, true},
+		{[]string{"timeout=abc, foo=bar, max=-1"}, KeepAlive{Timeout: -1, Max: -1}, false},
+		{[]string{""}, KeepAlive{Timeout: -1, Max: -1}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseKeepAlive(tt.values...)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseKeepAlive(%q) = %+v, %v; want %+v, %v", tt.values, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestKeepAlive_Reusable(t *testing.T) {
+	ka := KeepAlive{Timeout: 5 * time.Second, Max: 10}
+	if !ka.Reusable(2 * time.Second) {
+		t.Error("Expected connection idle for 2s to be reusable")
+	}
+	if ka.Reusable(4500 * time.Millisecond) {
+		t.Error("Expected connection idle within the safety margin to be retired")
+	}
+	if (KeepAlive{Timeout: 5 * time.Second, Max: 0}).Reusable(0) {
+		t.Error("Expected max=0 to forbid reuse")
+	}
+	if !(KeepAlive{Timeout: -1, Max: -1}).Reusable(time.Hour) {
+		t.Error("Expected no advertised timeout to leave reuse to the caller")
+	}
+}
+
+func TestKeepAlive_Reusable_Timeouts(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		idle  time.Duration
+		want  bool
+	}{
+		{"ZeroForbidsReuse", "timeout=0", 0, false},
+		{"OneSecondFresh", "timeout=1", 0, true},
+		{"OneSecondWithinHalf", "timeout=1", 400 * time.Millisecond, true},
+		{"OneSecondPastHalf", "timeout=1", 600 * time.Millisecond, false},
+		{"OverflowClamped", "timeout=99999999999999", 24 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ka, ok := ParseKeepAlive(tt.value)
+			if !ok {
+				t.Fatalf("ParseKeepAlive(%q) found nothing", tt.value)
+			}
+			if got := ka.Reusable(tt.idle); got != tt.want {
+				t.Errorf("%+v.Reusable(%v) = %v, want %v", ka, tt.idle, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package header
+
+import (
+	"fmt"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/url"
+)
+
+// Link represents a single link-value from a Link header (RFC 8288)
+type Link struct {
+	// URL is the target URI reference exactly as it appeared between the angle brackets.
+	URL string
+	// Rel holds the relation types from the rel parameter, lowercased.
+	Rel []string
+	// Params holds every link parameter by lowercased name, including rel.
+	// Only the first occurrence of a parameter is kept, as required by RFC 8288.
+	Params map[string]string
+}
+
+// HasRel reports whether the link carries the given relation type
+func (l Link) HasRel(rel string) bool {
+	for _, r := range l.Rel {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve resolves the link target against the URL of the response that
+// carried the header, so relative targets such as </items?page=2> work.
+// Without a base only absolute targets can be resolved; relative ones fail
+// with UrlParseFailure.
+func (l Link) Resolve(base *url.URL) (*url.URL, error) {
+	if base == nil {
+		return url.Parse(l.URL)
+	}
+	return base.Resolve(l.URL)
+}
+
+// ParseLinks parses one or more Link header field values.
+// Each value may itself contain several comma-separated link-values.
+func ParseLinks(values ...string) ([]Link, error) {
+	var links []Link
+	for _, v := range values {
+		p := &linkParser{s: v}
+		parsed, err := p.parse()
+		if err != nil {
+			return nil, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+		}
+		links = append(links, parsed...)
+	}
+	return links, nil
+}
+
+// LinksByRel indexes links by relation type.
+// When several links share a relation type, the first one wins.
+func LinksByRel(links []Link) map[string]Link {
+	byRel := make(map[string]Link)
+	for _, l := range links {
+		for _, r := range l.Rel {
+			if _, ok := byRel[r]; !ok {
+				byRel[r] = l
+			}
+		}
+	}
+	return byRel
+}
+
+type linkParser struct {
+	s   string
+	pos int
+}
+
+func (p *linkParser) parse() ([]Link, error) {
+	var links []Link
+	for {
+		p.skipOWS()
+		if p.eof() {
+			return links, nil
+		}
+		// Tolerate empty list elements as allowed by RFC 9110 section 5.6.1
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+
+		link, err := p.parseLinkValue()
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+
+		p.skipOWS()
+		if p.eof() {
+			return links, nil
+		}
+		if p.peek() != ',' {
+			return nil, fmt.Errorf("link: unexpected %q at offset %d", p.peek(), p.pos)
+		}
+		p.pos++
+	}
+}
+
+func (p *linkParser) parseLinkValue() (Link, error) {
+	if p.peek() != '<' {
+		return Link{}, fmt.Errorf("link: expected '<' at offset %d", p.pos)
+	}
+	end := strings.IndexByte(p.s[p.pos:], '>')
+	if end < 0 {
+		return Link{}, fmt.Errorf("link: unterminated URI reference at offset %d", p.pos)
+	}
+	link := Link{
+		URL:    strings.TrimSpace(p.s[p.pos+1 : p.pos+end]),
+		Params: make(map[string]string),
+	}
+	p.pos += end + 1
+
+	for {
+		p.skipOWS()
+		if p.eof() || p.peek() != ';' {
+			break
+		}
+		p.pos++
+		p.skipOWS()
+
+		name := strings.ToLower(p.token())
+		if name == "" {
+			// A trailing ";" with no parameter is harmless
+			if p.eof() || p.peek() == ',' {
+				break
+			}
+			return Link{}, fmt.Errorf("link: expected parameter name at offset %d", p.pos)
+		}
+
+		value := ""
+		p.skipOWS()
+		if !p.eof() && p.peek() == '=' {
+			p.pos++
+			p.skipOWS()
+			var err error
+			if value, err = p.paramValue(); err != nil {
+				return Link{}, err
+			}
+		}
+
+		if _, seen := link.Params[name]; !seen {
+			link.Params[name] = value
+		}
+	}
+
+	if rel, ok := link.Params["rel"]; ok {
+		link.Rel = strings.Fields(strings.ToLower(rel))
+	}
+	return link, nil
+}
+
+func (p *linkParser) paramValue() (string, error) {
+	if p.eof() {
+		return "", nil
+	}
+	if p.peek() != '"' {
+		// Be lenient with unquoted values such as type=text/html, which are
+		// common in practice even though "/" is not a token character
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(" \t;,", rune(p.s[p.pos])) {
+			p.pos++
+		}
+		return p.s[start:p.pos], nil
+	}
+
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", fmt.Errorf("link: unterminated quoted-string")
+			}
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("link: unterminated quoted-string")
+}
+
+func (p *linkParser) token() string {
+	start := p.pos
+	for !p.eof() && isTokenChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *linkParser) skipOWS() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *linkParser) peek() byte {
+	return p.s[p.pos]
+}
+
+func (p *linkParser) eof() bool {
+	return p.pos >= len(p.s)
+}
@@ -0,0 +1,131 @@
+package header
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/url"
+)
+
+func TestParseLinks_GitHubPagination(t *testing.T) {
+	value := `<https://api.github.com/repositories/1/issues?page=2>; rel="next", ` +
+		`<https://api.github.com/repositories/1/issues?page=5>; rel="last"`
+
+	links, err := ParseLinks(value)
+	if err != nil {
+		t.Fatalf("ParseLinks failed: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %d", len(links))
+	}
+
+	byRel := LinksByRel(links)
+	if got := byRel["next"].URL; got != "https://api.github.com/repositories/1/issues?page=2" {
+		t.Errorf("Unexpected next URL %q", got)
+	}
+	if got := byRel["last"].URL; got != "https://api.github.com/repositories/1/issues?page=5" {
+		t.Errorf("Unexpected last URL %q", got)
+	}
+}
+
+func TestParseLinks_Params(t *testing.T) {
+	links, err := ParseLinks(`</chapter2>; REL="next prefetch"; title="Chapter \"2\""; type=text/html; rel=ignored`)
+	if err != nil {
+		t.Fatalf("ParseLinks failed: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("Expected 1 link, got %d", len(links))
+	}
+
+	link := links[0]
+	if link.URL != "/chapter2" {
+		t.Errorf("Expected URL /chapter2, got %q", link.URL)
+	}
+	if !link.HasRel("next") || !link.HasRel("PREFETCH") {
+		t.Errorf("Expected rels next and prefetch, got %v", link.Rel)
+	}
+	if link.Params["title"] != `Chapter "2"` {
+		t.Errorf("Unexpected title %q", link.Params["title"])
+	}
+	if link.Params["type"] != "text/html" {
+		t.Errorf("Unexpected type %q", link.Params["type"])
+	}
+	if link.Params["rel"] != "next prefetch" {
+		t.Errorf("Expected first rel occurrence to win, got %q", link.Params["rel"])
+	}
+}
+
+func TestParseLinks_MultipleFieldValues(t *testing.T) {
+	links, err := ParseLinks(`<a>; rel=prev`, `<b,c>; rel=next;`, ``)
+	if err != nil {
+		t.Fatalf("ParseLinks failed: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %d", len(links))
+	}
+	if links[1].URL != "b,c" {
+		t.Errorf("Expected comma inside URI reference to be preserved, got %q", links[1].URL)
+	}
+}
+
+func TestParseLinks_Malformed(t *testing.T) {
+	tests := []string{
+		`https://example.com; rel=next`,
+		`<https://example.com; rel=next`,
+		`<a>; rel="next`,
+		`<a> rel=next`,
+		`<a>; =next`,
+	}
+
+	for _, value := range tests {
+		_, err := ParseLinks(value)
+		if err == nil {
+			t.Errorf("Expected error for %q", value)
+			continue
+		}
+
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok {
+			t.Fatalf("Expected *httperrors.Error, got %T", err)
+		}
+		if httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.HttpParseFailure {
+			t.Errorf("Expected HttpParseFailure for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestLink_Resolve_RelativeTarget(t *testing.T) {
+	links, err := ParseLinks(`</repositories/1/issues?page=3>; rel="next"`)
+	if err != nil {
+		t.Fatalf("ParseLinks failed: %v", err)
+	}
+	base, err := url.Parse("https://api.github.com/repositories/1/issues?page=2")
+	if err != nil {
+		t.Fatalf("url.Parse failed: %v", err)
+	}
+
+	next, err := LinksByRel(links)["next"].Resolve(base)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if next.String() != "https://api.github.com/repositories/1/issues?page=3" {
+		t.Errorf("Unexpected resolved URL %q", next.String())
+	}
+}
+
+func TestLink_Resolve_NilBase(t *testing.T) {
+	absolute := Link{URL: "https://example.com/items?page=2"}
+	resolved, err := absolute.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if resolved.String() != absolute.URL {
+		t.Errorf("Unexpected resolved URL %q", resolved.String())
+	}
+
+	_, err = Link{URL: "/items?page=2"}.Resolve(nil)
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.UrlParseFailure {
+		t.Errorf("Expected UrlParseFailure, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package header
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/header/sfv"
+)
+
+// epochThreshold separates X-RateLimit-Reset values given as Unix timestamps
+// (as GitHub does) from values given as delta seconds. Nobody advertises a
+// reset window of more than 30 years.
+const epochThreshold = 1_000_000_000
+
+// maxRateLimitSeconds is the largest number of seconds that fits in a
+// time.Duration
+const maxRateLimitSeconds = math.MaxInt64 / int64(time.Second)
+
+// RateLimitInfo is the rate-limit state advertised by a server
+type RateLimitInfo struct {
+	// Policy names the quota policy when the server advertises one.
+	Policy string
+	// Limit is the request quota for the current window, or -1 if unknown.
+	Limit int64
+	// Remaining is the number of requests left in the current window, or -1 if unknown.
+	Remaining int64
+	// Reset is when the quota is restored; the zero Time if unknown.
+	Reset time.Time
+	// Window is the length of the quota window; zero if unknown.
+	Window time.Duration
+}
+
+// Exhausted reports whether the server said no requests remain
+func (r RateLimitInfo) Exhausted() bool {
+	return r.Remaining == 0
+}
+
+// Delay returns how long a caller should wait before the next request.
+// It is zero unless the quota is exhausted and a reset time is known.
+func (r RateLimitInfo) Delay(now time.Time) time.Duration {
+	if !r.Exhausted() || r.Reset.IsZero() || !r.Reset.After(now) {
+		return 0
+	}
+	return r.Reset.Sub(now)
+}
+
+// ParseRateLimit extracts rate-limit information from response headers.
+// get returns the field value for a header name (case-insensitively), or ""
+// if absent. The structured RateLimit/RateLimit-Policy fields from the IETF
+// draft take precedence over the older RateLimit-* and the de-facto
+// X-RateLimit-* fields. The second result is false if none were present or
+// none could be parsed.
+func ParseRateLimit(get func(name string) string, now time.Time) (RateLimitInfo, bool) {
+	info := RateLimitInfo{Limit: -1, Remaining: -1}
+
+	found := parseStructuredRateLimit(get, now, &info)
+	if !found {
+		found = parseLegacyRateLimit(get, "RateLimit-", now, false, &info)
+	}
+	if !found {
+		found = parseLegacyRateLimit(get, "X-RateLimit-", now, true, &info)
+	}
+	return info, found
+}
+
+// parseStructuredRateLimit handles draft-ietf-httpapi-ratelimit-headers, e.g.
+//
+//	RateLimit-Policy: "burst";q=100;w=60
+//	RateLimit: "burst";r=0;t=30
+func parseStructuredRateLimit(get func(string) string, now time.Time, info *RateLimitInfo) bool {
+	value := get("RateLimit")
+	if value == "" {
+		return false
+	}
+	list, err := sfv.ParseList(value)
+	if err != nil || len(list) == 0 {
+		return false
+	}
+	// Servers may advertise several policies; the first one is the one
+	// closest to being exhausted
+	item, ok := list[0].(sfv.Item)
+	if !ok {
+		return false
+	}
+
+	info.Policy = sfvString(item.Value)
+	if r, ok := sfvInt(item.Params, "r"); ok {
+		info.Remaining = r
+	}
+	if t, ok := sfvInt(item.Params, "t"); ok {
+		info.Reset = now.Add(rateLimitSeconds(t))
+	}
+
+	if policies, err := sfv.ParseList(get("RateLimit-Policy")); err == nil {
+		for _, m := range policies {
+			policy, ok := m.(sfv.Item)
+			if !ok || sfvString(policy.Value) != info.Policy {
+				continue
+			}
+			if q, ok := sfvInt(policy.Params, "q"); ok {
+				info.Limit = q
+			}
+			if w, ok := sfvInt(policy.Params, "w"); ok {
+				info.Window = rateLimitSeconds(w)
+			}
+			break
+		}
+	}
+	return true
+}
+
+// parseLegacyRateLimit handles the Limit/Remaining/Reset triplet under the
+// given prefix. Reset is delta seconds unless epochReset allows Unix timestamps.
+func parseLegacyRateLimit(get func(string) string, prefix string, now time.Time, epochReset bool, info *RateLimitInfo) bool {
+	found := false
+
+	if limit, ok := parseRateLimitInt(get(prefix + "Limit")); ok {
+		info.Limit = limit
+		found = true
+	}
+	if remaining, ok := parseRateLimitInt(get(prefix + "Remaining")); ok {
+		info.Remaining = remaining
+		found = true
+	}
+	if reset, ok := parseRateLimitInt(get(prefix + "Reset")); ok {
+		if epochReset && reset >= epochThreshold {
+			info.Reset = time.Unix(reset, 0)
+		} else {
+			info.Reset = now.Add(rateLimitSeconds(reset))
+		}
+		found = true
+	}
+	return found
+}
+
+// parseRateLimitInt parses a non-negative integer, tolerating the
+// "100, 100;w=60" form where the first element is the effective value
+func parseRateLimitInt(value string) (int64, bool) {
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// rateLimitSeconds converts n seconds to a Duration, clamping values that
+// would overflow so a huge reset still means "wait" rather than a past time
+func rateLimitSeconds(n int64) time.Duration {
+	return time.Duration(min(n, maxRateLimitSeconds)) * time.Second
+}
+
+func sfvInt(params sfv.Params, key string) (int64, bool) {
+	v, ok := params.Get(key)
+	if !ok {
+		return 0, false
+	}
+	n, ok := v.(int64)
+	return n, ok && n >= 0
+}
+
+func sfvString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case sfv.Token:
+		return string(s)
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package header
+
+import (
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+func headerGetter(fields map[string]string) func(string) string {
+	h := textproto.MIMEHeader{}
+	for k, v := range fields {
+		h.Set(k, v)
+	}
+	return h.Get
+}
+
+func TestParseRateLimit_GitHub(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	get := headerGetter(map[string]string{
+		"x-ratelimit-limit":     "5000",
+		"x-ratelimit-remaining": "0",
+		"x-ratelimit-reset":     "1700000060",
+	})
+
+	info, ok := ParseRateLimit(get, now)
+	if !ok {
+		t.Fatal("Expected rate-limit info")
+	}
+	if info.Limit != 5000 || info.Remaining != 0 {
+		t.Errorf("Unexpected limit/remaining %d/%d", info.Limit, info.Remaining)
+	}
+	if !info.Exhausted() {
+		t.Error("Expected quota to be exhausted")
+	}
+	if d := info.Delay(now); d != time.Minute {
+		t.Errorf("Expected 1m delay, got %v", d)
+	}
+}
+
+func TestParseRateLimit_DeltaSecondsReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	get := headerGetter(map[string]string{
+		"RateLimit-Limit":     "100",
+		"RateLimit-Remaining": "42",
+		"RateLimit-Reset":     "30",
+	})
+
+	info, ok := ParseRateLimit(get, now)
+	if !ok {
+		t.Fatal("Expected rate-limit info")
+	}
+	if info.Remaining != 42 {
+		t.Errorf("Expected 42 remaining, got %d", info.Remaining)
+	}
+	if !info.Reset.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("Unexpected reset %v", info.Reset)
+	}
+	if d := info.Delay(now); d != 0 {
+		t.Errorf("Expected no delay while quota remains, got %v", d)
+	}
+}
+
+func TestParseRateLimit_Structured(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	get := headerGetter(map[string]string{
+		"RateLimit-Policy":      `"burst";q=100;w=60, "daily";q=1000;w=86400`,
+		"RateLimit":             `"burst";r=0;t=15`,
+		"X-RateLimit-Limit":     "1",
+		"X-RateLimit-Remaining": "1",
+	})
+
+	info, ok := ParseRateLimit(get, now)
+	if !ok {
+		t.Fatal("Expected rate-limit info")
+	}
+	if info.Policy != "burst" || info.Limit != 100 || info.Window != time.Minute {
+		t.Errorf("Unexpected policy %+v", info)
+	}
+	if info.Delay(now) != 15*time.Second {
+		t.Errorf("Expected 15s delay, got %v", info.Delay(now))
+	}
+}
+
+func TestParseRateLimit_Absent(t *testing.T) {
+	info, ok := ParseRateLimit(headerGetter(map[string]string{"X-RateLimit-Limit": "abc"}), time.Now())
+	if ok {
+		t.Errorf("Expected no rate-limit info, got %+v", info)
+	}
+	if info.Limit != -1 || info.Remaining != -1 {
+		t.Errorf("Expected unknown values to be -1, got %+v", info)
+	}
+}
+
+func TestParseRateLimit_HugeValuesClamped(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name   string
+		fields map[string]string
+	}{
+		{"StructuredReset", map[string]string{
+			"RateLimit-Policy": `"a";q=10;w=999999999999999`,
+			"RateLimit":        `"a";r=0;t=999999999999999`,
+		}},
+		{"LegacyReset", map[string]string{
+			"RateLimit-Remaining": "0",
+			"RateLimit-Reset":     "10000000000",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := ParseRateLimit(headerGetter(tt.fields), now)
+			if !ok {
+				t.Fatal("Expected rate-limit info")
+			}
+			if !info.Reset.After(now) {
+				t.Errorf("Expected reset in the future, got %v", info.Reset)
+			}
+			if d := info.Delay(now); d <= 0 {
+				t.Errorf("Expected a positive delay on an exhausted quota, got %v", d)
+			}
+			if info.Window < 0 {
+				t.Errorf("Expected a non-negative window, got %v", info.Window)
+			}
+		})
+	}
+}
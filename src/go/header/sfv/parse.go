@@ -0,0 +1,420 @@
+package sfv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// ParseItem parses a field value of type Item.
+// Multiple field lines must be joined with ", " by the caller.
+func ParseItem(value string) (Item, error) {
+	p := &parser{s: value}
+	p.skipSP()
+	item, err := p.parseItem()
+	if err == nil {
+		err = p.finish()
+	}
+	if err != nil {
+		return Item{}, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	return item, nil
+}
+
+// ParseList parses a field value of type List
+func ParseList(value string) (List, error) {
+	p := &parser{s: value}
+	p.skipSP()
+	list, err := p.parseList()
+	if err == nil {
+		err = p.finish()
+	}
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	return list, nil
+}
+
+// ParseDictionary parses a field value of type Dictionary
+func ParseDictionary(value string) (Dictionary, error) {
+	p := &parser{s: value}
+	p.skipSP()
+	dict, err := p.parseDictionary()
+	if err == nil {
+		err = p.finish()
+	}
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	return dict, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *parser) skipSP() {
+	for !p.eof() && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) skipOWS() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) finish() error {
+	p.skipSP()
+	if !p.eof() {
+		return p.errorf("unexpected trailing characters")
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("sfv: %s at offset %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+// RFC 8941 section 4.2.1
+func (p *parser) parseList() (List, error) {
+	var list List
+	for !p.eof() {
+		member, err := p.parseMember()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, member)
+
+		if done, err := p.memberSeparator(); done || err != nil {
+			return list, err
+		}
+	}
+	return list, nil
+}
+
+// RFC 8941 section 4.2.2
+func (p *parser) parseDictionary() (Dictionary, error) {
+	var dict Dictionary
+	for !p.eof() {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var member Member
+		if p.peek() == '=' {
+			p.pos++
+			if member, err = p.parseMember(); err != nil {
+				return nil, err
+			}
+		} else {
+			params, err := p.parseParameters()
+			if err != nil {
+				return nil, err
+			}
+			member = Item{Value: true, Params: params}
+		}
+		dict = dict.set(key, member)
+
+		if done, err := p.memberSeparator(); done || err != nil {
+			return dict, err
+		}
+	}
+	return dict, nil
+}
+
+// memberSeparator consumes the comma between list or dictionary members.
+// It reports done when the end of input has been reached.
+func (p *parser) memberSeparator() (bool, error) {
+	p.skipOWS()
+	if p.eof() {
+		return true, nil
+	}
+	if p.peek() != ',' {
+		return false, p.errorf("expected ','")
+	}
+	p.pos++
+	p.skipOWS()
+	if p.eof() {
+		return false, p.errorf("trailing ','")
+	}
+	return false, nil
+}
+
+func (p *parser) parseMember() (Member, error) {
+	if p.peek() == '(' {
+		return p.parseInnerList()
+	}
+	return p.parseItem()
+}
+
+// RFC 8941 section 4.2.1.2
+func (p *parser) parseInnerList() (InnerList, error) {
+	p.pos++ // consume '('
+	var items []Item
+	for !p.eof() {
+		p.skipSP()
+		if p.peek() == ')' {
+			p.pos++
+			params, err := p.parseParameters()
+			if err != nil {
+				return InnerList{}, err
+			}
+			return InnerList{Items: items, Params: params}, nil
+		}
+
+		item, err := p.parseItem()
+		if err != nil {
+			return InnerList{}, err
+		}
+		items = append(items, item)
+
+		if c := p.peek(); c != ' ' && c != ')' {
+			return InnerList{}, p.errorf("expected ' ' or ')' in inner list")
+		}
+	}
+	return InnerList{}, p.errorf("unterminated inner list")
+}
+
+// RFC 8941 section 4.2.3
+func (p *parser) parseItem() (Item, error) {
+	value, err := p.parseBareItem()
+	if err != nil {
+		return Item{}, err
+	}
+	params, err := p.parseParameters()
+	if err != nil {
+		return Item{}, err
+	}
+	return Item{Value: value, Params: params}, nil
+}
+
+// RFC 8941 section 4.2.3.1
+func (p *parser) parseBareItem() (any, error) {
+	c := p.peek()
+	switch {
+	case c == '-' || isDigit(c):
+		return p.parseNumber()
+	case c == '"':
+		return p.parseString()
+	case c == '*' || isAlpha(c):
+		return p.parseToken()
+	case c == ':':
+		return p.parseByteSequence()
+	case c == '?':
+		return p.parseBoolean()
+	case p.eof():
+		return nil, p.errorf("unexpected end of input")
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+// RFC 8941 section 4.2.3.2
+func (p *parser) parseParameters() (Params, error) {
+	var params Params
+	for p.peek() == ';' {
+		p.pos++
+		p.skipSP()
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value any = true
+		if p.peek() == '=' {
+			p.pos++
+			if value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+		params = params.set(key, value)
+	}
+	return params, nil
+}
+
+// RFC 8941 section 4.2.3.3
+func (p *parser) parseKey() (string, error) {
+	c := p.peek()
+	if c != '*' && !isLowerAlpha(c) {
+		return "", p.errorf("expected key")
+	}
+	start := p.pos
+	for !p.eof() {
+		c := p.s[p.pos]
+		if !isLowerAlpha(c) && !isDigit(c) && !strings.ContainsRune("_-.*", rune(c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos], nil
+}
+
+// RFC 8941 section 4.2.4
+func (p *parser) parseNumber() (any, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	if !isDigit(p.peek()) {
+		return nil, p.errorf("expected digit")
+	}
+
+	isDecimal := false
+	digits := 0
+	for !p.eof() {
+		c := p.s[p.pos]
+		if isDigit(c) {
+			digits++
+		} else if c == '.' && !isDecimal {
+			if digits > 12 {
+				return nil, p.errorf("decimal integer part too long")
+			}
+			isDecimal = true
+			digits = 0
+		} else {
+			break
+		}
+		p.pos++
+
+		if !isDecimal && digits > 15 {
+			return nil, p.errorf("integer too long")
+		}
+		if isDecimal && digits > 3 {
+			return nil, p.errorf("decimal fraction too long")
+		}
+	}
+
+	text := p.s[start:p.pos]
+	if !isDecimal {
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %q", text)
+		}
+		return n, nil
+	}
+
+	if strings.HasSuffix(text, ".") {
+		return nil, p.errorf("decimal without fraction")
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, p.errorf("invalid decimal %q", text)
+	}
+	return f, nil
+}
+
+// RFC 8941 section 4.2.5
+func (p *parser) parseString() (string, error) {
+	p.pos++ // consume '"'
+	var b strings.Builder
+	for !p.eof() {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			next := p.s[p.pos]
+			if next != '"' && next != '\\' {
+				return "", p.errorf("invalid escape")
+			}
+			b.WriteByte(next)
+			p.pos++
+		case c == '"':
+			return b.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", p.errorf("invalid string character")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// RFC 8941 section 4.2.6
+func (p *parser) parseToken() (Token, error) {
+	start := p.pos
+	p.pos++
+	for !p.eof() {
+		c := p.s[p.pos]
+		if !isTokenChar(c) && c != ':' && c != '/' {
+			break
+		}
+		p.pos++
+	}
+	return Token(p.s[start:p.pos]), nil
+}
+
+// RFC 8941 section 4.2.7
+func (p *parser) parseByteSequence() ([]byte, error) {
+	p.pos++ // consume ':'
+	end := strings.IndexByte(p.s[p.pos:], ':')
+	if end < 0 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+	encoded := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	// Senders should pad, but recipients may accept unpadded input
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(encoded, "="))
+		if err != nil {
+			return nil, p.errorf("invalid base64 in byte sequence")
+		}
+	}
+	return decoded, nil
+}
+
+// RFC 8941 section 4.2.8
+func (p *parser) parseBoolean() (bool, error) {
+	p.pos++ // consume '?'
+	switch p.peek() {
+	case '1':
+		p.pos++
+		return true, nil
+	case '0':
+		p.pos++
+		return false, nil
+	}
+	return false, p.errorf("invalid boolean")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLowerAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLowerAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+// isTokenChar reports whether c is a tchar as defined in RFC 9110 section 5.6.2
+func isTokenChar(c byte) bool {
+	if isAlpha(c) || isDigit(c) {
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
@@ -0,0 +1,183 @@
+package sfv
+
+import (
+	"bytes"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestParseItem_BareTypes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want any
+	}{
+		{"42", int64(42)},
+		{"-999999999999999", int64(-999999999999999)},
+		{"4.5", 4.5},
+		{"-0.125", -0.125},
+		{`"hello \"world\""`, `hello "world"`},
+		{"foo/bar:baz", Token("foo/bar:baz")},
+		{"*", Token("*")},
+		{"?1", true},
+		{"?0", false},
+	}
+
+	for _, tt := range tests {
+		item, err := ParseItem(tt.in)
+		if err != nil {
+			t.Errorf("ParseItem(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if item.Value != tt.want {
+			t.Errorf("ParseItem(%q) = %#v, want %#v", tt.in, item.Value, tt.want)
+		}
+	}
+}
+
+func TestParseItem_ByteSequence(t *testing.T) {
+	item, err := ParseItem(":cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg==:")
+	if err != nil {
+		t.Fatalf("ParseItem failed: %v", err)
+	}
+	got, ok := item.Value.([]byte)
+	if !ok {
+		t.Fatalf("Expected []byte, got %T", item.Value)
+	}
+	if !bytes.Equal(got, []byte("pretend this is binary content.")) {
+		t.Errorf("Unexpected bytes %q", got)
+	}
+}
+
+func TestParseItem_Params(t *testing.T) {
+	item, err := ParseItem(`text/html; charset=utf-8;q=0.5;final;a=1;a=2`)
+	if err != nil {
+		t.Fatalf("ParseItem failed: %v", err)
+	}
+	if item.Value != Token("text/html") {
+		t.Errorf("Unexpected value %#v", item.Value)
+	}
+
+	wantKeys := []string{"charset", "q", "final", "a"}
+	if len(item.Params) != len(wantKeys) {
+		t.Fatalf("Expected %d params, got %d", len(wantKeys), len(item.Params))
+	}
+	for i, k := range wantKeys {
+		if item.Params[i].Key != k {
+			t.Errorf("Param %d: expected key %q, got %q", i, k, item.Params[i].Key)
+		}
+	}
+	if v, _ := item.Params.Get("final"); v != true {
+		t.Errorf("Expected bare parameter to be true, got %#v", v)
+	}
+	if v, _ := item.Params.Get("a"); v != int64(2) {
+		t.Errorf("Expected last duplicate to win, got %#v", v)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	list, err := ParseList(`sugar, tea, (rum "cola");x=1,	?0`)
+	if err != nil {
+		t.Fatalf("ParseList failed: %v", err)
+	}
+	if len(list) != 4 {
+		t.Fatalf("Expected 4 members, got %d", len(list))
+	}
+
+	inner, ok := list[2].(InnerList)
+	if !ok {
+		t.Fatalf("Expected InnerList, got %T", list[2])
+	}
+	if len(inner.Items) != 2 || inner.Items[0].Value != Token("rum") || inner.Items[1].Value != "cola" {
+		t.Errorf("Unexpected inner list %#v", inner.Items)
+	}
+	if v, _ := inner.Params.Get("x"); v != int64(1) {
+		t.Errorf("Expected inner list parameter x=1, got %#v", v)
+	}
+}
+
+func TestParseList_Empty(t *testing.T) {
+	list, err := ParseList("")
+	if err != nil {
+		t.Fatalf("ParseList failed: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("Expected empty list, got %d members", len(list))
+	}
+}
+
+func TestParseDictionary(t *testing.T) {
+	dict, err := ParseDictionary(`u=1, i, hit;detail="x", u=3`)
+	if err != nil {
+		t.Fatalf("ParseDictionary failed: %v", err)
+	}
+
+	keys := dict.Keys()
+	if len(keys) != 3 || keys[0] != "u" || keys[1] != "i" || keys[2] != "hit" {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+
+	u, _ := dict.Get("u")
+	if u.(Item).Value != int64(3) {
+		t.Errorf("Expected last duplicate to win in original position, got %#v", u)
+	}
+	i, _ := dict.Get("i")
+	if i.(Item).Value != true {
+		t.Errorf("Expected bare key to be true, got %#v", i)
+	}
+	hit, _ := dict.Get("hit")
+	if v, _ := hit.(Item).Params.Get("detail"); v != "x" {
+		t.Errorf("Expected parameter on boolean member, got %#v", v)
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	items := []string{
+		"",
+		"1234567890123456",
+		"1234567890123.5",
+		"1.2345",
+		"1.",
+		`"unterminated`,
+		`"bad \escape"`,
+		":not base64!:",
+		"?2",
+		"a b",
+		"1;A=1",
+		"é",
+	}
+	for _, in := range items {
+		if _, err := ParseItem(in); err == nil {
+			t.Errorf("Expected ParseItem(%q) to fail", in)
+		}
+	}
+
+	lists := []string{"a,", "a,,b", "(a b", "(a,b)"}
+	for _, in := range lists {
+		if _, err := ParseList(in); err == nil {
+			t.Errorf("Expected ParseList(%q) to fail", in)
+		}
+	}
+
+	dicts := []string{"A=1", "a=1,", "a=?"}
+	for _, in := range dicts {
+		if _, err := ParseDictionary(in); err == nil {
+			t.Errorf("Expected ParseDictionary(%q) to fail", in)
+		}
+	}
+}
+
+func TestParse_ErrorType(t *testing.T) {
+	_, err := ParseItem("?x")
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok {
+		t.Fatalf("Expected *httperrors.Error, got %T", err)
+	}
+	if httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.HttpParseFailure {
+		t.Errorf("Expected HttpParseFailure, got %v", err)
+	}
+}
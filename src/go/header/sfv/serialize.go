@@ -0,0 +1,180 @@
+package sfv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// SerializeItem serializes an Item field value (RFC 8941 section 4.1.3).
+// Values that cannot be represented are reported as InvalidRequest.
+func SerializeItem(item Item) (string, error) {
+	var b strings.Builder
+	if err := writeItem(&b, item); err != nil {
+		return "", httperrors.NewHttpError(httperrors.InvalidRequest, err)
+	}
+	return b.String(), nil
+}
+
+// SerializeList serializes a List field value (RFC 8941 section 4.1.1)
+func SerializeList(list List) (string, error) {
+	var b strings.Builder
+	for i, m := range list {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if err := writeMember(&b, m); err != nil {
+			return "", httperrors.NewHttpError(httperrors.InvalidRequest, err)
+		}
+	}
+	return b.String(), nil
+}
+
+// SerializeDictionary serializes a Dictionary field value (RFC 8941 section 4.1.2)
+func SerializeDictionary(dict Dictionary) (string, error) {
+	var b strings.Builder
+	for i, m := range dict {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if err := writeKey(&b, m.Key); err != nil {
+			return "", httperrors.NewHttpError(httperrors.InvalidRequest, err)
+		}
+		// A true Boolean item is written as the bare key
+		if item, ok := m.Member.(Item); ok && item.Value == true {
+			if err := writeParams(&b, item.Params); err != nil {
+				return "", httperrors.NewHttpError(httperrors.InvalidRequest, err)
+			}
+			continue
+		}
+		b.WriteByte('=')
+		if err := writeMember(&b, m.Member); err != nil {
+			return "", httperrors.NewHttpError(httperrors.InvalidRequest, err)
+		}
+	}
+	return b.String(), nil
+}
+
+func writeMember(b *strings.Builder, m Member) error {
+	switch m := m.(type) {
+	case Item:
+		return writeItem(b, m)
+	case InnerList:
+		b.WriteByte('(')
+		for i, item := range m.Items {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			if err := writeItem(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(')')
+		return writeParams(b, m.Params)
+	}
+	return fmt.Errorf("sfv: unsupported member type %T", m)
+}
+
+func writeItem(b *strings.Builder, item Item) error {
+	if err := writeBareItem(b, item.Value); err != nil {
+		return err
+	}
+	return writeParams(b, item.Params)
+}
+
+func writeParams(b *strings.Builder, params Params) error {
+	for _, p := range params {
+		b.WriteByte(';')
+		if err := writeKey(b, p.Key); err != nil {
+			return err
+		}
+		if p.Value == true {
+			continue
+		}
+		b.WriteByte('=')
+		if err := writeBareItem(b, p.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RFC 8941 section 4.1.1.3
+func writeKey(b *strings.Builder, key string) error {
+	if key == "" || !(isLowerAlpha(key[0]) || key[0] == '*') {
+		return fmt.Errorf("sfv: invalid key %q", key)
+	}
+	for i := 1; i < len(key); i++ {
+		c := key[i]
+		if !isLowerAlpha(c) && !isDigit(c) && strings.IndexByte("_-.*", c) < 0 {
+			return fmt.Errorf("sfv: invalid key %q", key)
+		}
+	}
+	b.WriteString(key)
+	return nil
+}
+
+const maxInteger = 999_999_999_999_999
+
+// RFC 8941 sections 4.1.3.1 through 4.1.9
+func writeBareItem(b *strings.Builder, v any) error {
+	switch v := v.(type) {
+	case int64:
+		if v > maxInteger || v < -maxInteger {
+			return fmt.Errorf("sfv: integer %d out of range", v)
+		}
+		b.WriteString(strconv.FormatInt(v, 10))
+	case int:
+		return writeBareItem(b, int64(v))
+	case float64:
+		rounded := math.RoundToEven(v*1000) / 1000
+		if math.IsNaN(v) || math.Abs(rounded) >= 1e12 {
+			return fmt.Errorf("sfv: decimal %v out of range", v)
+		}
+		s := strconv.FormatFloat(rounded, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		b.WriteString(s)
+	case string:
+		b.WriteByte('"')
+		for i := 0; i < len(v); i++ {
+			c := v[i]
+			if c < 0x20 || c > 0x7e {
+				return fmt.Errorf("sfv: invalid character %q in string", c)
+			}
+			if c == '"' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+		b.WriteByte('"')
+	case Token:
+		if v == "" || !(isAlpha(v[0]) || v[0] == '*') {
+			return fmt.Errorf("sfv: invalid token %q", v)
+		}
+		for i := 1; i < len(v); i++ {
+			if !isTokenChar(v[i]) && v[i] != ':' && v[i] != '/' {
+				return fmt.Errorf("sfv: invalid token %q", v)
+			}
+		}
+		b.WriteString(string(v))
+	case []byte:
+		b.WriteByte(':')
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		b.WriteByte(':')
+	case bool:
+		if v {
+			b.WriteString("?1")
+		} else {
+			b.WriteString("?0")
+		}
+	default:
+		return fmt.Errorf("sfv: unsupported bare item type %T", v)
+	}
+	return nil
+}
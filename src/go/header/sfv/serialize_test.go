@@ -0,0 +1,92 @@
+package sfv
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestSerializeItem_BareTypes(t *testing.T) {
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{int64(42), "42"},
+		{-17, "-17"},
+		{4.5, "4.5"},
+		{2.0, "2.0"},
+		{1.0004, "1.0"},
+		{0.0025, "0.002"},
+		{`say "hi" \o/`, `"say \"hi\" \\o/"`},
+		{Token("foo/bar:baz"), "foo/bar:baz"},
+		{[]byte("pretend this is binary content."), ":cHJldGVuZCB0aGlzIGlzIGJpbmFyeSBjb250ZW50Lg==:"},
+		{true, "?1"},
+		{false, "?0"},
+	}
+	for _, tt := range tests {
+		got, err := SerializeItem(Item{Value: tt.in})
+		if err != nil {
+			t.Errorf("SerializeItem(%#v) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("SerializeItem(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSerializeItem_Invalid(t *testing.T) {
+	for _, v := range []any{
+		int64(1_000_000_000_000_000),
+		1e12,
+		"tab\there",
+		Token("1abc"),
+		Token("a b"),
+		struct{}{},
+	} {
+		_, err := SerializeItem(Item{Value: v})
+		if err == nil {
+			t.Errorf("Expected error serializing %#v", v)
+			continue
+		}
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.InvalidRequest {
+			t.Errorf("Expected InvalidRequest for %#v, got %v", v, err)
+		}
+	}
+
+	if _, err := SerializeItem(Item{Value: int64(1), Params: Params{{Key: "Upper", Value: true}}}); err == nil {
+		t.Error("Expected error for an uppercase parameter key")
+	}
+}
+
+func TestSerializeList(t *testing.T) {
+	list := List{
+		Item{Value: Token("sugar"), Params: Params{{Key: "a", Value: true}, {Key: "q", Value: 0.5}}},
+		InnerList{Items: []Item{{Value: "foo"}, {Value: int64(2)}}, Params: Params{{Key: "lvl", Value: int64(1)}}},
+		InnerList{},
+	}
+	got, err := SerializeList(list)
+	if err != nil {
+		t.Fatalf("SerializeList failed: %v", err)
+	}
+	want := `sugar;a;q=0.5, ("foo" 2);lvl=1, ()`
+	if got != want {
+		t.Errorf("SerializeList = %q, want %q", got, want)
+	}
+}
+
+func TestSerializeDictionary_RoundTrip(t *testing.T) {
+	in := `sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:, fresh, b=?0;x=1, c=(1 2)`
+	dict, err := ParseDictionary(in)
+	if err != nil {
+		t.Fatalf("ParseDictionary failed: %v", err)
+	}
+	got, err := SerializeDictionary(dict)
+	if err != nil {
+		t.Fatalf("SerializeDictionary failed: %v", err)
+	}
+	if got != in {
+		t.Errorf("Round trip gave %q, want %q", got, in)
+	}
+}
@@ -0,0 +1,106 @@
+// Package sfv parses and serializes Structured Field Values for HTTP (RFC 8941).
+//
+// Bare item values are represented with plain Go types:
+//
+//	Integer       int64
+//	Decimal       float64
+//	String        string
+//	Token         Token
+//	Byte Sequence []byte
+//	Boolean       bool
+package sfv
+
+// Token is a bare item of type Token, kept distinct from String
+type Token string
+
+// Param is a single key/value parameter attached to an item or inner list
+type Param struct {
+	Key   string
+	Value any
+}
+
+// Params is an ordered set of parameters
+type Params []Param
+
+// Get returns the value of the parameter with the given key
+func (p Params) Get(key string) (any, bool) {
+	for _, param := range p {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces an existing key in place, keeping the original position as
+// required when a key is repeated, or appends a new one
+func (p Params) set(key string, value any) Params {
+	for i := range p {
+		if p[i].Key == key {
+			p[i].Value = value
+			return p
+		}
+	}
+	return append(p, Param{Key: key, Value: value})
+}
+
+// Member is a member of a List or Dictionary: either an Item or an InnerList
+type Member interface {
+	isMember()
+}
+
+// Item is a bare item with its parameters
+type Item struct {
+	Value  any
+	Params Params
+}
+
+// InnerList is a parenthesized list of items with its own parameters
+type InnerList struct {
+	Items  []Item
+	Params Params
+}
+
+func (Item) isMember()      {}
+func (InnerList) isMember() {}
+
+// List is a top-level structured List
+type List []Member
+
+// DictMember is a single key/member pair of a Dictionary
+type DictMember struct {
+	Key    string
+	Member Member
+}
+
+// Dictionary is an ordered top-level structured Dictionary
+type Dictionary []DictMember
+
+// Get returns the member with the given key
+func (d Dictionary) Get(key string) (Member, bool) {
+	for _, m := range d {
+		if m.Key == key {
+			return m.Member, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the dictionary keys in order
+func (d Dictionary) Keys() []string {
+	keys := make([]string, len(d))
+	for i, m := range d {
+		keys[i] = m.Key
+	}
+	return keys
+}
+
+func (d Dictionary) set(key string, member Member) Dictionary {
+	for i := range d {
+		if d[i].Key == key {
+			d[i].Member = member
+			return d
+		}
+	}
+	return append(d, DictMember{Key: key, Member: member})
+}
@@ -0,0 +1,124 @@
+// Package testserver provides scripted server behaviors for tests that need
+// responses to arrive the way slow or awkward servers send them: one byte at
+// a time, split across many packets, or with a stall between header and body.
+//
+// A script is a plain func(net.Conn), so it plugs directly into the existing
+// per-package test server setup helpers.
+package testserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Step is one action performed on the server side of a connection.
+// A non-nil error aborts the remaining steps.
+type Step func(conn net.Conn) error
+
+// Script runs the steps in order on the accepted connection
+func Script(steps ...Step) func(net.Conn) {
+	return func(conn net.Conn) {
+		for _, step := range steps {
+			if err := step(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Write sends data in a single write
+func Write(data []byte) Step {
+	return func(conn net.Conn) error {
+		_, err := conn.Write(data)
+		return err
+	}
+}
+
+// Dribble sends data one byte per write, pausing delay between bytes
+func Dribble(data []byte, delay time.Duration) Step {
+	return Chunked(data, 1, delay)
+}
+
+// Chunked sends data in writes of at most size bytes, pausing delay between them.
+// TCP_NODELAY is enabled on TCP connections so each write goes out as its own segment.
+// It panics if size is not positive.
+func Chunked(data []byte, size int, delay time.Duration) Step {
+	if size <= 0 {
+		panic(fmt.Sprintf("testserver: Chunked size %d must be positive", size))
+	}
+	return func(conn net.Conn) error {
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			tcpConn.SetNoDelay(true)
+		}
+		for len(data) > 0 {
+			n := min(size, len(data))
+			if _, err := conn.Write(data[:n]); err != nil {
+				return err
+			}
+			data = data[n:]
+			if len(data) > 0 && delay > 0 {
+				time.Sleep(delay)
+			}
+		}
+		return nil
+	}
+}
+
+// Pause waits before the next step
+func Pause(d time.Duration) Step {
+	return func(conn net.Conn) error {
+		time.Sleep(d)
+		return nil
+	}
+}
+
+// StallAfterHeaders sends an HTTP response's header section, waits for stall,
+// then sends the body. Responses without a blank line are sent whole.
+func StallAfterHeaders(response []byte, stall time.Duration) Step {
+	return func(conn net.Conn) error {
+		end := bytes.Index(response, []byte("\r\n\r\n"))
+		if end < 0 {
+			_, err := conn.Write(response)
+			return err
+		}
+		end += 4
+		if _, err := conn.Write(response[:end]); err != nil {
+			return err
+		}
+		time.Sleep(stall)
+		if end == len(response) {
+			return nil
+		}
+		_, err := conn.Write(response[end:])
+		return err
+	}
+}
+
+// ReadRequestHead consumes an HTTP request's header section, up to and
+// including the blank line, so that responses are only sent once the client
+// has finished writing. Any request body is left partially buffered and lost.
+func ReadRequestHead() Step {
+	return func(conn net.Conn) error {
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			if line == "\r\n" || line == "\n" {
+				return nil
+			}
+		}
+	}
+}
+
+// Close closes the connection early, e.g. to simulate an abrupt disconnect
+// in the middle of a response
+func Close() Step {
+	return func(conn net.Conn) error {
+		return conn.Close()
+	}
+}
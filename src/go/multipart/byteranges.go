@@ -0,0 +1,115 @@
+package multipart
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	mimemultipart "mime/multipart"
+	"net/textproto"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/header"
+)
+
+// ByteRange is one part of a multipart/byteranges response
+type ByteRange struct {
+	// Range is the parsed Content-Range of the part.
+	Range header.ContentRange
+	// ContentType is the part's Content-Type, if any.
+	ContentType string
+	// Header holds all part headers.
+	Header textproto.MIMEHeader
+	// Body yields exactly Range.Length() bytes. It is only valid until the
+	// next call to Next.
+	Body io.Reader
+}
+
+// ByteRangesReader iterates over the parts of a multipart/byteranges body
+type ByteRangesReader struct {
+	mr      *mimemultipart.Reader
+	current *ByteRange
+}
+
+// NewByteRangesReader creates a reader for a multipart/byteranges body.
+// contentType is the response's Content-Type field value.
+func NewByteRangesReader(contentType string, body io.Reader) (*ByteRangesReader, error) {
+	boundary, err := boundaryFromContentType(contentType, "multipart/byteranges")
+	if err != nil {
+		return nil, err
+	}
+	return &ByteRangesReader{mr: mimemultipart.NewReader(body, boundary)}, nil
+}
+
+// Next advances to the next part, discarding any unread data of the current
+// one. It returns io.EOF after the last part.
+func (r *ByteRangesReader) Next() (*ByteRange, error) {
+	if r.current != nil {
+		// A short part means the server sent less than its Content-Range promised
+		if _, err := io.Copy(io.Discard, r.current.Body); err != nil {
+			return nil, err
+		}
+		r.current = nil
+	}
+
+	part, err := r.mr.NextRawPart()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+
+	value := part.Header.Get("Content-Range")
+	if value == "" {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: byteranges part without Content-Range"))
+	}
+	cr, err := header.ParseContentRange(value)
+	if err != nil {
+		return nil, err
+	}
+	if cr.First < 0 {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: unsatisfied range %q in byteranges part", value))
+	}
+
+	r.current = &ByteRange{
+		Range:       cr,
+		ContentType: part.Header.Get("Content-Type"),
+		Header:      part.Header,
+		Body:        &exactReader{r: part, remaining: cr.Length()},
+	}
+	return r.current, nil
+}
+
+// exactReader enforces that a part is exactly as long as its Content-Range says
+type exactReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (e *exactReader) Read(p []byte) (int, error) {
+	if e.remaining <= 0 {
+		// Anything beyond the declared range is a framing error
+		var probe [1]byte
+		if n, _ := e.r.Read(probe[:]); n > 0 {
+			return 0, httperrors.NewHttpError(httperrors.HttpParseFailure,
+				fmt.Errorf("multipart: part longer than its Content-Range"))
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > e.remaining {
+		p = p[:e.remaining]
+	}
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+	if errors.Is(err, io.EOF) {
+		if e.remaining > 0 {
+			return n, httperrors.NewHttpError(httperrors.HttpParseFailure,
+				fmt.Errorf("multipart: part shorter than its Content-Range"))
+		}
+		err = nil
+	}
+	return n, err
+}
@@ -0,0 +1,133 @@
+package multipart
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+const byterangesBody = "--THIS_STRING_SEPARATES\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Range: bytes 500-509/8000\r\n" +
+	"\r\n" +
+	"0123456789\r\n" +
+	"--THIS_STRING_SEPARATES\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Range: bytes 7000-7004/8000\r\n" +
+	"\r\n" +
+	"abcde\r\n" +
+	"--THIS_STRING_SEPARATES--\r\n"
+
+const byterangesType = "multipart/byteranges; boundary=THIS_STRING_SEPARATES"
+
+func expectParseFailure(t *testing.T, err error) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatal("Expected HttpParseFailure, got nil")
+	}
+	var httpErr *httperrors.Error
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected *httperrors.Error, got %T", err)
+	}
+	if httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.HttpParseFailure {
+		t.Errorf("Expected HttpParseFailure, got %v", err)
+	}
+}
+
+func TestByteRangesReader(t *testing.T) {
+	r, err := NewByteRangesReader(byterangesType, strings.NewReader(byterangesBody))
+	if err != nil {
+		t.Fatalf("NewByteRangesReader failed: %v", err)
+	}
+
+	want := []struct {
+		first, last int64
+		data        string
+	}{
+		{500, 509, "0123456789"},
+		{7000, 7004, "abcde"},
+	}
+	for i, w := range want {
+		part, err := r.Next()
+		if err != nil {
+			t.Fatalf("Part %d: Next failed: %v", i, err)
+		}
+		if part.Range.First != w.first || part.Range.Last != w.last || part.Range.Complete != 8000 {
+			t.Errorf("Part %d: unexpected range %+v", i, part.Range)
+		}
+		if part.ContentType != "application/pdf" {
+			t.Errorf("Part %d: unexpected content type %q", i, part.ContentType)
+		}
+		data, err := io.ReadAll(part.Body)
+		if err != nil {
+			t.Fatalf("Part %d: reading body failed: %v", i, err)
+		}
+		if string(data) != w.data {
+			t.Errorf("Part %d: expected %q, got %q", i, w.data, data)
+		}
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after last part, got %v", err)
+	}
+}
+
+func TestByteRangesReader_SkipsUnreadParts(t *testing.T) {
+	r, err := NewByteRangesReader(byterangesType, strings.NewReader(byterangesBody))
+	if err != nil {
+		t.Fatalf("NewByteRangesReader failed: %v", err)
+	}
+
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	part, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if part.Range.First != 7000 {
+		t.Errorf("Expected second part, got %+v", part.Range)
+	}
+}
+
+func TestByteRangesReader_LengthMismatch(t *testing.T) {
+	tests := map[string]string{
+		"short": "--b\r\nContent-Range: bytes 0-9/100\r\n\r\n01234\r\n--b--\r\n",
+		"long":  "--b\r\nContent-Range: bytes 0-1/100\r\n\r\n01234\r\n--b--\r\n",
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewByteRangesReader("multipart/byteranges; boundary=b", strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("NewByteRangesReader failed: %v", err)
+			}
+			part, err := r.Next()
+			if err != nil {
+				t.Fatalf("Next failed: %v", err)
+			}
+			_, err = io.ReadAll(part.Body)
+			expectParseFailure(t, err)
+		})
+	}
+}
+
+func TestByteRangesReader_MissingContentRange(t *testing.T) {
+	body := "--b\r\nContent-Type: text/plain\r\n\r\nhello\r\n--b--\r\n"
+	r, err := NewByteRangesReader("multipart/byteranges; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewByteRangesReader failed: %v", err)
+	}
+	_, err = r.Next()
+	expectParseFailure(t, err)
+}
+
+func TestNewByteRangesReader_BadContentType(t *testing.T) {
+	for _, ct := range []string{"text/plain", "multipart/byteranges", "multipart/mixed; boundary=b", ";;"} {
+		_, err := NewByteRangesReader(ct, strings.NewReader(""))
+		expectParseFailure(t, err)
+	}
+}
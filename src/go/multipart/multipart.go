@@ -0,0 +1,29 @@
+// Package multipart parses multipart response bodies incrementally, reading
+// parts straight from the body stream instead of buffering the whole response.
+package multipart
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// boundaryFromContentType checks the media type and returns its boundary parameter
+func boundaryFromContentType(contentType string, wantPrefix string) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+	if !strings.HasPrefix(mediaType, wantPrefix) {
+		return "", httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: unexpected media type %q", mediaType))
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return "", httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: missing boundary parameter"))
+	}
+	return boundary, nil
+}
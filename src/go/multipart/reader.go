@@ -0,0 +1,138 @@
+package multipart
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	mimemultipart "mime/multipart"
+	"net/textproto"
+	"strconv"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// DefaultMaxPartSize is the part size limit applied by NewReader
+const DefaultMaxPartSize = 16 << 20
+
+// Part is one part of a streaming multipart body
+type Part struct {
+	// Header holds all part headers.
+	Header textproto.MIMEHeader
+	// ContentType is the part's Content-Type, if any.
+	ContentType string
+	// Body yields the part's content. When the part has a Content-Length it
+	// ends after that many bytes without waiting for the next boundary.
+	// It is only valid until the next call to Next.
+	Body io.Reader
+}
+
+// Reader iterates over the parts of a multipart body as they arrive, for
+// long-lived responses such as multipart/x-mixed-replace camera feeds or
+// watch APIs that never end
+type Reader struct {
+	// MaxPartSize bounds how many bytes a single part may contain; a longer
+	// part fails with HttpParseFailure. Zero means no limit.
+	MaxPartSize int64
+	mr          *mimemultipart.Reader
+	current     *Part
+}
+
+// NewReader creates a reader for any multipart body. contentType is the
+// response's Content-Type field value.
+func NewReader(contentType string, body io.Reader) (*Reader, error) {
+	boundary, err := boundaryFromContentType(contentType, "multipart/")
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		MaxPartSize: DefaultMaxPartSize,
+		mr:          mimemultipart.NewReader(body, boundary),
+	}, nil
+}
+
+// Next blocks until the next part's headers have arrived, discarding any
+// unread data of the current part. It returns io.EOF after the closing boundary.
+func (r *Reader) Next() (*Part, error) {
+	if r.current != nil {
+		if _, err := io.Copy(io.Discard, r.current.Body); err != nil {
+			return nil, err
+		}
+		r.current = nil
+	}
+
+	part, err := r.mr.NextRawPart()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.HttpParseFailure, err)
+	}
+
+	var body io.Reader = part
+	limit := r.MaxPartSize
+	if value := part.Header.Get("Content-Length"); value != "" {
+		length, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || length < 0 {
+			return nil, httperrors.NewHttpError(httperrors.HttpParseFailure,
+				fmt.Errorf("multipart: invalid part Content-Length %q", value))
+		}
+		if limit > 0 && length > limit {
+			return nil, httperrors.NewHttpError(httperrors.HttpParseFailure,
+				fmt.Errorf("multipart: part Content-Length %d exceeds limit of %d bytes", length, limit))
+		}
+		body = &lengthReader{r: part, remaining: length}
+		limit = 0
+	}
+	if limit > 0 {
+		body = &boundedReader{r: body, remaining: limit}
+	}
+
+	r.current = &Part{
+		Header:      part.Header,
+		ContentType: part.Header.Get("Content-Type"),
+		Body:        body,
+	}
+	return r.current, nil
+}
+
+// lengthReader ends a part after its declared Content-Length without reading
+// ahead to the boundary, so a frame is delivered as soon as it is complete
+type lengthReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *lengthReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if errors.Is(err, io.EOF) && l.remaining > 0 {
+		return n, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: part shorter than its Content-Length"))
+	}
+	if l.remaining == 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// boundedReader fails once a part without Content-Length grows past the limit
+type boundedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (b *boundedReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, httperrors.NewHttpError(httperrors.HttpParseFailure,
+			fmt.Errorf("multipart: part exceeds size limit"))
+	}
+	return n, err
+}
@@ -0,0 +1,168 @@
+package multipart
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReader_MixedReplaceStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	r, err := NewReader("multipart/x-mixed-replace; boundary=frame", pr)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	frames := []string{"first-frame", "second"}
+	next := make(chan struct{})
+	go func() {
+		for _, f := range frames {
+			io.WriteString(pw, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: "+
+				strconv.Itoa(len(f))+"\r\n\r\n"+f+"\r\n")
+			// Hold the next frame back until the reader has consumed this one
+			<-next
+		}
+		io.WriteString(pw, "--frame--\r\n")
+		pw.Close()
+	}()
+
+	for _, want := range frames {
+		done := make(chan struct{})
+		var part *Part
+		var data []byte
+		go func() {
+			defer close(done)
+			part, err = r.Next()
+			if err == nil {
+				data, err = io.ReadAll(part.Body)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Frame %q was not delivered before the next boundary arrived", want)
+		}
+		if err != nil {
+			t.Fatalf("Reading frame failed: %v", err)
+		}
+		if part.ContentType != "image/jpeg" {
+			t.Errorf("Expected image/jpeg, got %q", part.ContentType)
+		}
+		if string(data) != want {
+			t.Errorf("Expected frame %q, got %q", want, data)
+		}
+		next <- struct{}{}
+	}
+
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF after closing boundary, got %v", err)
+	}
+}
+
+func TestReader_PartsWithoutContentLength(t *testing.T) {
+	body := "--b\r\nX-Seq: 1\r\n\r\nalpha\r\n" +
+		"--b\r\nX-Seq: 2\r\n\r\nbeta\r\n" +
+		"--b--\r\n"
+	r, err := NewReader("multipart/mixed; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	for _, want := range []struct{ seq, data string }{{"1", "alpha"}, {"2", "beta"}} {
+		part, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if got := part.Header.Get("X-Seq"); got != want.seq {
+			t.Errorf("Expected X-Seq %s, got %q", want.seq, got)
+		}
+		data, err := io.ReadAll(part.Body)
+		if err != nil {
+			t.Fatalf("Reading part failed: %v", err)
+		}
+		if string(data) != want.data {
+			t.Errorf("Expected %q, got %q", want.data, data)
+		}
+	}
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_SkipsUnreadParts(t *testing.T) {
+	body := "--b\r\nContent-Length: 3\r\n\r\nabc\r\n" +
+		"--b\r\n\r\nunread\r\n" +
+		"--b\r\n\r\nlast\r\n" +
+		"--b--\r\n"
+	r, err := NewReader("multipart/mixed; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Next(); err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+	}
+	part, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	data, _ := io.ReadAll(part.Body)
+	if string(data) != "last" {
+		t.Errorf("Expected last part, got %q", data)
+	}
+}
+
+func TestReader_MaxPartSize(t *testing.T) {
+	body := "--b\r\n\r\n" + strings.Repeat("x", 100) + "\r\n--b--\r\n"
+	r, err := NewReader("multipart/mixed; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	r.MaxPartSize = 10
+
+	part, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	_, err = io.ReadAll(part.Body)
+	expectParseFailure(t, err)
+}
+
+func TestReader_MaxPartSize_ContentLength(t *testing.T) {
+	body := "--b\r\nContent-Length: 100\r\n\r\n" + strings.Repeat("x", 100) + "\r\n--b--\r\n"
+	r, err := NewReader("multipart/mixed; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	r.MaxPartSize = 10
+
+	_, err = r.Next()
+	expectParseFailure(t, err)
+}
+
+func TestReader_ShortContentLength(t *testing.T) {
+	body := "--b\r\nContent-Length: 10\r\n\r\nabc\r\n--b--\r\n"
+	r, err := NewReader("multipart/mixed; boundary=b", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	part, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	_, err = io.ReadAll(part.Body)
+	expectParseFailure(t, err)
+}
+
+func TestNewReader_NotMultipart(t *testing.T) {
+	_, err := NewReader("image/jpeg", strings.NewReader(""))
+	expectParseFailure(t, err)
+}
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// expectPeerReset connects t to a server that records how its read of the
+// connection ended, runs closeFn, and checks the server saw a reset
+func expectPeerReset(t *testing.T, transport *TcpTransport, closeFn func() error) {
+	t.Helper()
+
+	readErr := make(chan error, 1)
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err := io.ReadAll(conn)
+		readErr <- err
+	})
+	defer cleanup()
+
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := closeFn(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	err := <-readErr
+	if !isConnectionClosed(err) {
+		t.Errorf("Expected the server to see a connection reset, got %v", err)
+	}
+}
+
+func TestTcpTransport_Abort_SendsReset(t *testing.T) {
+	transport := NewTcpTransport()
+	expectPeerReset(t, transport, transport.Abort)
+
+	if err := transport.Abort(); err != nil {
+		t.Errorf("Expected second Abort to be a no-op, got %v", err)
+	}
+}
+
+func TestTcpTransport_AbortiveClose_SendsReset(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.AbortiveClose = true
+	expectPeerReset(t, transport, transport.Close)
+}
+
+func TestTcpTransport_Close_IsGracefulByDefault(t *testing.T) {
+	readErr := make(chan error, 1)
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err := io.ReadAll(conn)
+		readErr <- err
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := <-readErr; err != nil {
+		t.Errorf("Expected a clean EOF on graceful close, got %v", err)
+	}
+}
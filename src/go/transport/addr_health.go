@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultFailedAddrTTL is how long a failed address is deprioritized
+const DefaultFailedAddrTTL = 30 * time.Second
+
+// AddrHealth remembers addresses that recently failed to connect so that
+// subsequent dials try healthy addresses first. Addresses are ip:port pairs,
+// so a refused port on one service does not demote other services on the
+// same host. It is safe for concurrent use and is meant to be shared between
+// transports.
+type AddrHealth struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	failed map[string]time.Time
+	now    func() time.Time
+}
+
+// NewAddrHealth creates an AddrHealth that forgets failures after ttl
+func NewAddrHealth(ttl time.Duration) *AddrHealth {
+	return &AddrHealth{
+		ttl:    ttl,
+		failed: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// defaultAddrHealth is shared by transports that were not given their own
+var defaultAddrHealth = NewAddrHealth(DefaultFailedAddrTTL)
+
+// MarkFailed records a connect failure for addr
+func (h *AddrHealth) MarkFailed(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failed[addr] = h.now()
+}
+
+// MarkHealthy forgets any recorded failure for addr
+func (h *AddrHealth) MarkHealthy(addr string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.failed, addr)
+}
+
+// RecentlyFailed reports whether addr failed within the TTL
+func (h *AddrHealth) RecentlyFailed(addr string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.recentlyFailedLocked(addr)
+}
+
+func (h *AddrHealth) recentlyFailedLocked(addr string) bool {
+	at, ok := h.failed[addr]
+	if !ok {
+		return false
+	}
+	if h.now().Sub(at) >= h.ttl {
+		delete(h.failed, addr)
+		return false
+	}
+	return true
+}
+
+// Order returns the addresses with those that recently failed on port moved
+// to the end.
+// Failed addresses are kept rather than dropped so that a host whose every
+// address failed recently is still retried. The relative order of resolver
+// results is otherwise preserved.
+func (h *AddrHealth) Order(addrs []net.IPAddr, port uint16) []net.IPAddr {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ordered := make([]net.IPAddr, 0, len(addrs))
+	var failed []net.IPAddr
+	for _, a := range addrs {
+		if h.recentlyFailedLocked(net.JoinHostPort(a.String(), strconv.Itoa(int(port)))) {
+			failed = append(failed, a)
+		} else {
+			ordered = append(ordered, a)
+		}
+	}
+	return append(ordered, failed...)
+}
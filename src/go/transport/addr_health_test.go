@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAddrHealth_OrderMovesFailedToEnd(t *testing.T) {
+	h := NewAddrHealth(time.Minute)
+	addrs := []net.IPAddr{
+		{IP: net.ParseIP("10.0.0.1")},
+		{IP: net.ParseIP("10.0.0.2")},
+		{IP: net.ParseIP("10.0.0.3")},
+	}
+
+	h.MarkFailed("10.0.0.1:443")
+	h.MarkFailed("10.0.0.2:80")
+	ordered := h.Order(addrs, 443)
+
+	want := []string{"10.0.0.2", "10.0.0.3", "10.0.0.1"}
+	for i, a := range ordered {
+		if a.String() != want[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, want[i], a.String())
+		}
+	}
+}
+
+func TestAddrHealth_FailureExpires(t *testing.T) {
+	h := NewAddrHealth(time.Minute)
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	h.MarkFailed("10.0.0.1:80")
+	if !h.RecentlyFailed("10.0.0.1:80") {
+		t.Fatal("Expected address to be marked as failed")
+	}
+
+	now = now.Add(time.Minute)
+	if h.RecentlyFailed("10.0.0.1:80") {
+		t.Error("Expected failure to expire after the TTL")
+	}
+}
+
+func TestAddrHealth_MarkHealthy(t *testing.T) {
+	h := NewAddrHealth(time.Minute)
+	h.MarkFailed("10.0.0.1:80")
+	h.MarkHealthy("10.0.0.1:80")
+	if h.RecentlyFailed("10.0.0.1:80") {
+		t.Error("Expected MarkHealthy to clear the failure")
+	}
+}
+
+func TestTcpTransport_Connect_FailsOverToNextAddress(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {})
+	defer cleanup()
+
+	// Nothing listens on 127.0.0.2, so the first address is refused
+	dead := net.IPAddr{IP: net.ParseIP("127.0.0.2")}
+	live := net.IPAddr{IP: net.ParseIP(host)}
+
+	transport := NewTcpTransport()
+	transport.Health = NewAddrHealth(time.Minute)
+	transport.lookupIPAddr = func(ctx context.Context, name string) ([]net.IPAddr, error) {
+		return []net.IPAddr{dead, live}, nil
+	}
+
+	if err := transport.Connect("cluster.test", port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	deadAddr := net.JoinHostPort(dead.String(), strconv.Itoa(int(port)))
+	liveAddr := net.JoinHostPort(live.String(), strconv.Itoa(int(port)))
+	if !transport.Health.RecentlyFailed(deadAddr) {
+		t.Errorf("Expected %s to be remembered as failed", deadAddr)
+	}
+	if transport.Health.RecentlyFailed(liveAddr) {
+		t.Errorf("Expected %s to be healthy", liveAddr)
+	}
+	if transport.Health.RecentlyFailed(net.JoinHostPort(dead.String(), "1")) {
+		t.Error("Expected the failure to be scoped to the dialed port")
+	}
+}
+
+func TestTcpTransport_Connect_BlackholedAddressDoesNotBlockFailover(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		buf := make([]byte, 1)
+		conn.Read(buf)
+	})
+	defer cleanup()
+
+	// 192.0.2.1 is TEST-NET-1; the dial hook makes it swallow SYNs
+	blackholed := net.IPAddr{IP: net.ParseIP("192.0.2.1")}
+	live := net.IPAddr{IP: net.ParseIP(host)}
+	blackholedAddr := net.JoinHostPort(blackholed.String(), strconv.Itoa(int(port)))
+
+	transport := NewTcpTransport()
+	transport.Health = NewAddrHealth(time.Minute)
+	transport.Options.ConnectTimeout = 10 * time.Second
+	transport.lookupIPAddr = func(ctx context.Context, name string) ([]net.IPAddr, error) {
+		return []net.IPAddr{blackholed, live}, nil
+	}
+	var dialer net.Dialer
+	transport.dialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == blackholedAddr {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	start := time.Now()
+	if err := transport.Connect("cluster.test", port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected failover well within the connect timeout, took %v", elapsed)
+	}
+}
+
+func TestPartialDeadline(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		addrs     int
+		want      time.Duration
+	}{
+		{"SplitEvenly", 30 * time.Second, 3, 10 * time.Second},
+		{"SaneMinimum", 5 * time.Second, 5, 2 * time.Second},
+		{"LessThanMinimumLeft", time.Second, 2, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partialDeadline(now, now.Add(tt.remaining), tt.addrs).Sub(now)
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
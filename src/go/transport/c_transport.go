@@ -0,0 +1,195 @@
+//go:build cgo && httpc_cgo
+
+package transport
+
+/*
+#cgo CFLAGS: -std=gnu2x -I${SRCDIR}/../../../include
+#cgo LDFLAGS: -L${SRCDIR}/../../../build_release/src/c -lhttpc_lib -Wl,-rpath,${SRCDIR}/../../../build_release/src/c
+
+#include <stdlib.h>
+#include <sys/socket.h>
+#include <sys/time.h>
+#include <httpc/tcp_transport.h>
+#include <httpc/unix_transport.h>
+
+// cgo cannot call through C function pointers, so each vtable entry gets a
+// small trampoline.
+static Error httpgo_c_connect(TransportInterface* t, const char* host, int port) {
+    return t->connect(t->context, host, port);
+}
+
+static Error httpgo_c_write(TransportInterface* t, const void* buf, size_t len, ssize_t* n) {
+    return t->write(t->context, buf, len, n);
+}
+
+static Error httpgo_c_read(TransportInterface* t, void* buf, size_t len, ssize_t* n) {
+    return t->read(t->context, buf, len, n);
+}
+
+static Error httpgo_c_close(TransportInterface* t) {
+    return t->close(t->context);
+}
+
+static void httpgo_c_destroy(TransportInterface* t) {
+    t->destroy(t->context);
+}
+
+// libhttpc has no timeout support, so timeouts are set on its socket
+// directly. SO_RCVTIMEO and SO_SNDTIMEO make read and write fail with
+// EAGAIN once they expire.
+static int httpgo_c_set_timeout(TransportInterface* t, int is_unix, int optname, long long usec) {
+    int fd = is_unix ? ((UnixClient*)t->context)->fd : ((TcpClient*)t->context)->fd;
+    struct timeval tv = { .tv_sec = usec / 1000000, .tv_usec = usec % 1000000 };
+    return setsockopt(fd, SOL_SOCKET, optname, &tv, sizeof(tv));
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"syscall"
+	"time"
+	"unsafe"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// CTransport implements the Transport interface by calling into the C
+// libhttpc transport layer. It is only built with the httpc_cgo tag and
+// expects libhttpc_lib from a build_release CMake build, so benchmarks can
+// compare Go's net stack against the C sockets code in one binary.
+//
+// CTransport does not implement ContextTransport: a blocking libhttpc call
+// cannot be interrupted from Go without tearing down the socket. Use the
+// Options timeouts to bound reads and writes instead.
+type CTransport struct {
+	// Options holds the timeouts. ReadTimeout and WriteTimeout are applied
+	// with SO_RCVTIMEO and SO_SNDTIMEO after each connect; ConnectTimeout
+	// is not supported by the C transport.
+	Options TransportOptions
+	iface   *C.TransportInterface
+	unix    bool
+}
+
+// NewCTcpTransport creates a CTransport backed by the C TCP transport
+func NewCTcpTransport() *CTransport {
+	return &CTransport{iface: C.tcp_transport_new(nil)}
+}
+
+// NewCUnixTransport creates a CTransport backed by the C Unix socket transport
+func NewCUnixTransport() *CTransport {
+	return &CTransport{iface: C.unix_transport_new(nil), unix: true}
+}
+
+// Connect establishes a connection to the specified host and port
+func (t *CTransport) Connect(host string, port uint16) error {
+	if t.iface == nil {
+		return httperrors.NewTransportError(httperrors.InitFailure, nil)
+	}
+
+	chost := C.CString(host)
+	defer C.free(unsafe.Pointer(chost))
+	if err := fromCError(C.httpgo_c_connect(t.iface, chost, C.int(port))); err != nil {
+		return err
+	}
+	if err := t.setTimeout(C.SO_RCVTIMEO, t.Options.ReadTimeout); err != nil {
+		return err
+	}
+	return t.setTimeout(C.SO_SNDTIMEO, t.Options.WriteTimeout)
+}
+
+// setTimeout sets a socket timeout option on the connected socket
+func (t *CTransport) setTimeout(optname C.int, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	unix := C.int(0)
+	if t.unix {
+		unix = 1
+	}
+	// A timeval of zero would mean no timeout at all
+	usec := max(timeout.Microseconds(), 1)
+	if rc, err := C.httpgo_c_set_timeout(t.iface, unix, optname, C.longlong(usec)); rc != 0 {
+		C.httpgo_c_close(t.iface)
+		return httperrors.NewTransportError(httperrors.InitFailure, err)
+	}
+	return nil
+}
+
+// Write sends data over the connection
+func (t *CTransport) Write(buf []byte) (int, error) {
+	if t.iface == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	var n C.ssize_t
+	e, errno := C.httpgo_c_write(t.iface, unsafe.Pointer(&buf[0]), C.size_t(len(buf)), &n)
+	if err := fromCError(e); err != nil {
+		if isErrnoTimeout(errno) {
+			return 0, httperrors.NewTransportError(httperrors.Timeout, errno)
+		}
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// Read receives data from the connection
+func (t *CTransport) Read(buf []byte) (int, error) {
+	if t.iface == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	var n C.ssize_t
+	e, errno := C.httpgo_c_read(t.iface, unsafe.Pointer(&buf[0]), C.size_t(len(buf)), &n)
+	if err := fromCError(e); err != nil {
+		if isErrnoTimeout(errno) {
+			return 0, httperrors.NewTransportError(httperrors.Timeout, errno)
+		}
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// Close closes the connection. The underlying C transport is kept so the
+// CTransport can be reconnected; call Destroy to release it.
+func (t *CTransport) Close() error {
+	if t.iface == nil {
+		return nil
+	}
+	return fromCError(C.httpgo_c_close(t.iface))
+}
+
+// Destroy closes the connection and frees the C transport
+func (t *CTransport) Destroy() {
+	if t.iface == nil {
+		return
+	}
+	C.httpgo_c_close(t.iface)
+	C.httpgo_c_destroy(t.iface)
+	t.iface = nil
+}
+
+// fromCError maps an httpc Error to the equivalent Go error. The C transport
+// codes start at 1 (0 is NONE) but otherwise line up with TransportError.
+func fromCError(e C.Error) error {
+	switch e._type {
+	case 0:
+		return nil
+	case 1:
+		return httperrors.NewTransportError(httperrors.TransportError(e.code-1), nil)
+	default:
+		return httperrors.NewTransportError(httperrors.InitFailure, nil)
+	}
+}
+
+// isErrnoTimeout reports whether errno, as left behind by a failed C read
+// or write, means that SO_RCVTIMEO or SO_SNDTIMEO expired
+func isErrnoTimeout(errno error) bool {
+	return errors.Is(errno, syscall.EAGAIN) || errors.Is(errno, syscall.EWOULDBLOCK)
+}
@@ -0,0 +1,26 @@
+//go:build cgo && httpc_cgo
+
+package transport
+
+import (
+	"net"
+	"testing"
+)
+
+func init() {
+	conformanceBackends = append(conformanceBackends, conformanceBackend{
+		name: "c-tcp",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			ct := NewCTcpTransport()
+			ct.Options = opts
+			if handler == nil {
+				return ct, "127.0.0.1", 65531, ct.Destroy
+			}
+			host, port, cleanup := setupTcpTestServer(t, handler)
+			return ct, host, port, func() {
+				ct.Destroy()
+				cleanup()
+			}
+		},
+	})
+}
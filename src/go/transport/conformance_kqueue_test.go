@@ -0,0 +1,23 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package transport
+
+import (
+	"net"
+	"testing"
+)
+
+func init() {
+	conformanceBackends = append(conformanceBackends, conformanceBackend{
+		name: "kqueue",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			tr := NewKqueueTransport()
+			tr.Options = opts
+			if handler == nil {
+				return tr, "127.0.0.1", 65531, func() {}
+			}
+			host, port, cleanup := setupTcpTestServer(t, handler)
+			return tr, host, port, cleanup
+		},
+	})
+}
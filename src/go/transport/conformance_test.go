@@ -0,0 +1,230 @@
+package transport
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// conformanceBackend knows how to run a connected or unconnected instance of
+// one Transport implementation for the shared conformance scenarios
+type conformanceBackend struct {
+	name string
+	// start returns a transport that has not connected yet, configured with
+	// opts, the address to connect it to, and a cleanup function. A nil
+	// handler means the address must be unreachable.
+	start func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func())
+}
+
+var conformanceBackends = []conformanceBackend{
+	{
+		name: "tcp",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			tr := NewTcpTransport()
+			tr.Options = opts
+			if handler == nil {
+				return tr, "127.0.0.1", 65531, func() {}
+			}
+			host, port, cleanup := setupTcpTestServer(t, handler)
+			return tr, host, port, cleanup
+		},
+	},
+	{
+		name: "tls",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			cert, pool := newTestCertificate(t)
+			tr := NewTlsTransport(&tls.Config{RootCAs: pool})
+			tr.Tcp.Options = opts
+			if handler == nil {
+				return tr, "127.0.0.1", 65531, func() {}
+			}
+			host, port, cleanup := setupTlsTestServer(t, cert, handler)
+			return tr, host, port, cleanup
+		},
+	},
+	{
+		name: "unix",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			tr := NewUnixTransport()
+			tr.Options = opts
+			if handler == nil {
+				return tr, filepath.Join(os.TempDir(), "httpgo_test_nonexistent.sock"), 0, func() {}
+			}
+			path, cleanup := setupUnixTestServer(t, handler)
+			return tr, path, 0, cleanup
+		},
+	},
+	{
+		name: "sim",
+		start: func(t *testing.T, handler func(net.Conn), opts TransportOptions) (Transport, string, uint16, func()) {
+			tr := NewSimTransport(SimLink{}, handler)
+			tr.Options = opts
+			return tr, "sim", 0, func() {}
+		},
+	},
+}
+
+// conformanceScenario is one behavior every transport must classify the same way.
+// A zero want means the final operation must succeed.
+type conformanceScenario struct {
+	name    string
+	handler func(net.Conn)
+	options TransportOptions
+	run     func(tr Transport, host string, port uint16) error
+	want    *httperrors.TransportError
+}
+
+func transportErrorPtr(te httperrors.TransportError) *httperrors.TransportError {
+	return &te
+}
+
+var conformanceScenarios = []conformanceScenario{
+	{
+		name: "connect to unreachable peer",
+		run: func(tr Transport, host string, port uint16) error {
+			return tr.Connect(host, port)
+		},
+		want: transportErrorPtr(httperrors.SocketConnectFailure),
+	},
+	{
+		name: "read without connect",
+		run: func(tr Transport, host string, port uint16) error {
+			_, err := tr.Read(make([]byte, 16))
+			return err
+		},
+		want: transportErrorPtr(httperrors.SocketReadFailure),
+	},
+	{
+		name: "write without connect",
+		run: func(tr Transport, host string, port uint16) error {
+			_, err := tr.Write([]byte("x"))
+			return err
+		},
+		want: transportErrorPtr(httperrors.SocketWriteFailure),
+	},
+	{
+		name: "close without connect",
+		run: func(tr Transport, host string, port uint16) error {
+			return tr.Close()
+		},
+	},
+	{
+		name:    "read after peer closes gracefully",
+		handler: func(conn net.Conn) {},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			_, err := tr.Read(make([]byte, 16))
+			return err
+		},
+		want: transportErrorPtr(httperrors.ConnectionClosed),
+	},
+	{
+		name: "read past data sent before peer close",
+		handler: func(conn net.Conn) {
+			conn.Write([]byte("partial"))
+		},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			buf := make([]byte, 16)
+			total := 0
+			for {
+				n, err := tr.Read(buf)
+				total += n
+				if err != nil {
+					if total != len("partial") {
+						return nil // reported as an unexpected success below
+					}
+					return err
+				}
+			}
+		},
+		want: transportErrorPtr(httperrors.ConnectionClosed),
+	},
+	{
+		name:    "read after local close",
+		handler: func(conn net.Conn) {},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			tr.Close()
+			_, err := tr.Read(make([]byte, 16))
+			return err
+		},
+		want: transportErrorPtr(httperrors.SocketReadFailure),
+	},
+	{
+		name:    "write after local close",
+		handler: func(conn net.Conn) {},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			tr.Close()
+			_, err := tr.Write([]byte("x"))
+			return err
+		},
+		want: transportErrorPtr(httperrors.SocketWriteFailure),
+	},
+	{
+		name: "read from stalled peer with ReadTimeout",
+		handler: func(conn net.Conn) {
+			// Send nothing and wait for the client to give up
+			conn.Read(make([]byte, 1))
+		},
+		options: TransportOptions{ReadTimeout: 50 * time.Millisecond},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			_, err := tr.Read(make([]byte, 16))
+			return err
+		},
+		want: transportErrorPtr(httperrors.Timeout),
+	},
+	{
+		name:    "double close",
+		handler: func(conn net.Conn) {},
+		run: func(tr Transport, host string, port uint16) error {
+			if err := tr.Connect(host, port); err != nil {
+				return err
+			}
+			if err := tr.Close(); err != nil {
+				return err
+			}
+			return tr.Close()
+		},
+	},
+}
+
+// TestTransportConformance runs every scenario against every backend so that
+// error classification cannot drift between Transport implementations
+func TestTransportConformance(t *testing.T) {
+	for _, backend := range conformanceBackends {
+		for _, sc := range conformanceScenarios {
+			t.Run(backend.name+"/"+sc.name, func(t *testing.T) {
+				tr, host, port, cleanup := backend.start(t, sc.handler, sc.options)
+				defer cleanup()
+				defer tr.Close()
+
+				err := sc.run(tr, host, port)
+				if sc.want == nil {
+					if err != nil {
+						t.Errorf("Expected success, got %v", err)
+					}
+					return
+				}
+				expectTransportError(t, err, *sc.want)
+			})
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// ContextTransport is implemented by transports whose operations can be
+// cancelled. When ctx is cancelled the operation fails with Cancelled, and
+// when its deadline passes with Timeout; the context's error is the
+// underlying error in both cases.
+//
+// All transports implement it except CTransport, whose blocking C calls
+// cannot be interrupted.
+type ContextTransport interface {
+	Transport
+	ConnectCtx(ctx context.Context, host string, port uint16) error
+	WriteCtx(ctx context.Context, buf []byte) (int, error)
+	ReadCtx(ctx context.Context, buf []byte) (int, error)
+}
+
+// contextError translates a done context into Cancelled or Timeout
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return httperrors.NewTransportError(httperrors.Timeout, err)
+	}
+	return httperrors.NewTransportError(httperrors.Cancelled, err)
+}
+
+// deadliner is the part of net.Conn used to interrupt blocked I/O
+type deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+// withContext runs op and interrupts it by expiring the connection's deadline
+// if ctx is done first. The deadline is cleared again afterwards, so the
+// connection stays usable after a cancelled Read.
+func withContext(ctx context.Context, conn deadliner, op func() (int, error)) (int, error) {
+	if ctx.Done() == nil {
+		return op()
+	}
+	if ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+		close(interrupted)
+	})
+	n, err := op()
+	if !stop() {
+		<-interrupted
+		conn.SetDeadline(time.Time{})
+		return n, contextError(ctx)
+	}
+	return n, err
+}
@@ -0,0 +1,258 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestTcpTransport_ReadCtx_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+		conn.Write([]byte("late"))
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Cancelled)
+
+	// The connection survives a cancelled read
+	close(release)
+	buf := make([]byte, 16)
+	n, err := transport.ReadCtx(context.Background(), buf)
+	if err != nil {
+		t.Fatalf("Read after cancellation failed: %v", err)
+	}
+	if string(buf[:n]) != "late" {
+		t.Errorf("Expected late, got %q", buf[:n])
+	}
+}
+
+func TestTcpTransport_ReadCtx_Deadline(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestTcpTransport_ReadCtx_CancelWithReadTimeout(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	transport.Options.ReadTimeout = 5 * time.Second
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	// Cancelling around the time ReadTimeout is armed must not lose the cancel
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		start := time.Now()
+		_, err := transport.ReadCtx(ctx, make([]byte, 16))
+		expectTransportError(t, err, httperrors.Cancelled)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Cancelled read took %v", elapsed)
+		}
+	}
+
+	// A context deadline sooner than ReadTimeout wins
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Timeout)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Read timed out after %v, expected about 50ms", elapsed)
+	}
+}
+
+// writeUntilBlocked writes to a peer that never reads until the socket
+// buffers fill up and WriteCtx blocks, returning its error
+func writeUntilBlocked(tr ContextTransport, ctx context.Context) error {
+	buf := make([]byte, 1<<20)
+	for {
+		if _, err := tr.WriteCtx(ctx, buf); err != nil {
+			return err
+		}
+	}
+}
+
+func TestTcpTransport_WriteCtx_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	err := writeUntilBlocked(transport, ctx)
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestTcpTransport_WriteCtx_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := writeUntilBlocked(transport, ctx)
+	expectTransportError(t, err, httperrors.Timeout)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Write timed out after %v, expected about 50ms", elapsed)
+	}
+}
+
+func TestUnixTransport_WriteCtx_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	path, cleanup := setupUnixTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	err := writeUntilBlocked(transport, ctx)
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestSimTransport_ReadCtx_Cancel(t *testing.T) {
+	transport := NewSimTransport(SimLink{}, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Cancelled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestSimTransport_WriteCtx_AlreadyCancelled(t *testing.T) {
+	transport := NewSimTransport(SimLink{}, echoHandler)
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := transport.WriteCtx(ctx, []byte("x"))
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestTcpTransport_ConnectCtx_AlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewTcpTransport().ConnectCtx(ctx, "127.0.0.1", 65531)
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestTcpTransport_ConnectCtx_HungResolver(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := transport.ConnectCtx(ctx, "example.invalid", 80)
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestUnixTransport_ReadCtx_Cancel(t *testing.T) {
+	path, cleanup := setupUnixTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestTlsTransport_ConnectCtx_StalledHandshake(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		// Accept the TCP connection but never answer the ClientHello
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		conn.Read(make([]byte, 4096))
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := NewTlsTransport(&tls.Config{}).ConnectCtx(ctx, host, port)
+	expectTransportError(t, err, httperrors.Timeout)
+}
@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DialAttempt records a single failed connection attempt
+type DialAttempt struct {
+	// Addr is the host:port that was dialed.
+	Addr string
+	// Err is the error returned for this address.
+	Err error
+	// Errno is the underlying system error number, or 0 if there was none
+	// (e.g. the attempt failed before reaching the kernel).
+	Errno syscall.Errno
+	// Duration is how long the attempt took.
+	Duration time.Duration
+}
+
+// DialError describes why a connect failed after trying every resolved
+// address. It is the underlying error of a SocketConnectFailure returned by
+// TcpTransport.Connect and can be retrieved with errors.As.
+type DialError struct {
+	Host string
+	Port uint16
+	// Resolve is how long name resolution took.
+	Resolve time.Duration
+	// Attempts lists every address tried, in order.
+	Attempts []DialAttempt
+}
+
+func newDialAttempt(addr string, err error, duration time.Duration) DialAttempt {
+	attempt := DialAttempt{Addr: addr, Err: err, Duration: duration}
+	errors.As(err, &attempt.Errno)
+	return attempt
+}
+
+func (e *DialError) Error() string {
+	if len(e.Attempts) == 0 {
+		return fmt.Sprintf("dial %s:%d: no addresses to try", e.Host, e.Port)
+	}
+
+	parts := make([]string, len(e.Attempts))
+	for i, a := range e.Attempts {
+		reason := a.Err.Error()
+		if a.Errno != 0 {
+			reason = a.Errno.Error()
+		}
+		parts[i] = fmt.Sprintf("%s: %s (%v)", a.Addr, reason, a.Duration.Round(time.Microsecond))
+	}
+	return fmt.Sprintf("dial %s:%d: %d address(es) failed: %s",
+		e.Host, e.Port, len(e.Attempts), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the individual attempts
+func (e *DialError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, a := range e.Attempts {
+		errs[i] = a.Err
+	}
+	return errs
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestTcpTransport_Connect_DialErrorListsAttempts(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.Health = NewAddrHealth(time.Minute)
+	transport.lookupIPAddr = func(ctx context.Context, name string) ([]net.IPAddr, error) {
+		return []net.IPAddr{
+			{IP: net.ParseIP("127.0.0.2")},
+			{IP: net.ParseIP("127.0.0.3")},
+		}, nil
+	}
+
+	err := transport.Connect("cluster.test", 65531)
+	expectTransportError(t, err, httperrors.SocketConnectFailure)
+
+	var dialErr *DialError
+	if !errors.As(err, &dialErr) {
+		t.Fatalf("Expected *DialError, got %v", err)
+	}
+	if dialErr.Host != "cluster.test" || dialErr.Port != 65531 {
+		t.Errorf("Unexpected target %s:%d", dialErr.Host, dialErr.Port)
+	}
+	if len(dialErr.Attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(dialErr.Attempts))
+	}
+
+	for i, want := range []string{"127.0.0.2:65531", "127.0.0.3:65531"} {
+		attempt := dialErr.Attempts[i]
+		if attempt.Addr != want {
+			t.Errorf("Attempt %d: expected %s, got %s", i, want, attempt.Addr)
+		}
+		if !isConnectionRefused(attempt.Errno) {
+			t.Errorf("Attempt %d: expected connection refused errno, got %v", i, attempt.Errno)
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error message to mention %s: %v", want, err)
+		}
+	}
+
+	if !isConnectionRefused(err) {
+		t.Error("Expected errors.Is to reach the per-attempt errno")
+	}
+}
+
+func TestDialError_NoAddresses(t *testing.T) {
+	err := &DialError{Host: "empty.test", Port: 80}
+	if !strings.Contains(err.Error(), "no addresses") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
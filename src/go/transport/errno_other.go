@@ -0,0 +1,24 @@
+//go:build !windows
+
+package transport
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isConnectionClosed reports whether err means the peer closed or reset the connection
+func isConnectionClosed(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// isConnectionRefused reports whether err means nothing accepted the connection
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isAddrNotAvailable reports whether err means no local address or ephemeral
+// port was free for the connection
+func isAddrNotAvailable(err error) bool {
+	return errors.Is(err, syscall.EADDRNOTAVAIL)
+}
@@ -0,0 +1,34 @@
+package transport
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Winsock and Win32 error numbers the syscall package does not define.
+// The POSIX-named constants in syscall are invented values on Windows and
+// never match what the network stack actually returns.
+const (
+	errnoWSAEADDRNOTAVAIL syscall.Errno = 10049
+	errnoWSAECONNREFUSED  syscall.Errno = 10061
+	errnoERROR_NO_DATA    syscall.Errno = 232
+)
+
+// isConnectionClosed reports whether err means the peer closed or reset the connection
+func isConnectionClosed(err error) bool {
+	return errors.Is(err, syscall.WSAECONNRESET) ||
+		errors.Is(err, syscall.WSAECONNABORTED) ||
+		errors.Is(err, syscall.ERROR_BROKEN_PIPE) ||
+		errors.Is(err, errnoERROR_NO_DATA)
+}
+
+// isConnectionRefused reports whether err means nothing accepted the connection
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, errnoWSAECONNREFUSED)
+}
+
+// isAddrNotAvailable reports whether err means no local address or ephemeral
+// port was free for the connection
+func isAddrNotAvailable(err error) bool {
+	return errors.Is(err, errnoWSAEADDRNOTAVAIL)
+}
@@ -0,0 +1,25 @@
+package transport
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func expectTransportError(t *testing.T, err error, want httperrors.TransportError) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("Expected %v, got nil", want)
+	}
+	httpErr, ok := err.(*httperrors.Error)
+	if !ok {
+		t.Fatalf("Expected *httperrors.Error, got %T", err)
+	}
+	if httpErr.TransportErr == nil {
+		t.Fatal("Expected TransportError")
+	}
+	if *httpErr.TransportErr != want {
+		t.Errorf("Expected %v, got %v", want, *httpErr.TransportErr)
+	}
+}
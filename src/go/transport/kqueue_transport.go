@@ -0,0 +1,314 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// KqueueTransport implements the Transport interface with non-blocking TCP
+// sockets driven directly by kqueue, bypassing the Go runtime's netpoller.
+// It exists for comparing async I/O backends in the benchmarks; waits block
+// the calling OS thread, so it is not meant for highly concurrent use.
+// Waits check for context cancellation every kqueueCancelPoll.
+type KqueueTransport struct {
+	// Options holds the connect, read and write timeouts.
+	Options TransportOptions
+	fd      int
+	kq      int
+}
+
+// kqueueCancelPoll bounds each kevent wait while a cancellable context is
+// in use, since nothing else wakes a thread blocked in kevent when ctx is
+// cancelled
+const kqueueCancelPoll = 20 * time.Millisecond
+
+// NewKqueueTransport creates a new KqueueTransport instance
+func NewKqueueTransport() *KqueueTransport {
+	return &KqueueTransport{
+		fd: -1,
+		kq: -1,
+	}
+}
+
+// Connect establishes a TCP connection to the specified host and port,
+// trying each resolved address in turn
+func (t *KqueueTransport) Connect(host string, port uint16) error {
+	return t.ConnectCtx(context.Background(), host, port)
+}
+
+// ConnectCtx is Connect with cancellation of both name resolution and dialing
+func (t *KqueueTransport) ConnectCtx(ctx context.Context, host string, port uint16) error {
+	// Release the socket and kqueue of any previous connection
+	t.Close()
+
+	ctx, cancel := t.Options.connectContext(ctx)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	resolveStart := time.Now()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	dialErr := &DialError{Host: host, Port: port, Resolve: time.Since(resolveStart)}
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.DnsFailure, err)
+	}
+
+	kq, err := syscall.Kqueue()
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.InitFailure, err)
+	}
+	syscall.CloseOnExec(kq)
+
+	for _, ip := range addrs {
+		addr := net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+		start := time.Now()
+		fd, err := dialKqueue(ctx, kq, ip, int(port), deadline)
+		if err != nil {
+			if ctx.Err() != nil {
+				syscall.Close(kq)
+				return contextError(ctx)
+			}
+			if isTimeout(err) {
+				syscall.Close(kq)
+				return httperrors.NewTransportError(httperrors.Timeout, err)
+			}
+			var te *httperrors.Error
+			if errors.As(err, &te) && te.TransportErr != nil {
+				// Socket creation or setup failures are not address-specific
+				syscall.Close(kq)
+				return err
+			}
+			dialErr.Attempts = append(dialErr.Attempts, newDialAttempt(addr, err, time.Since(start)))
+			continue
+		}
+
+		t.fd = fd
+		t.kq = kq
+		return nil
+	}
+
+	syscall.Close(kq)
+	return httperrors.NewTransportError(httperrors.SocketConnectFailure, dialErr)
+}
+
+// dialKqueue performs a non-blocking connect to one address and waits for it
+// to complete. Connection errors are returned as plain *net.OpError values;
+// local setup failures are returned already classified.
+func dialKqueue(ctx context.Context, kq int, ip net.IPAddr, port int, deadline time.Time) (int, error) {
+	sa, family, err := sockaddrFor(ip, port)
+	if err != nil {
+		return -1, &net.OpError{Op: "dial", Net: "tcp", Err: err}
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_STREAM, syscall.IPPROTO_TCP)
+	if err != nil {
+		return -1, httperrors.NewTransportError(httperrors.SocketCreateFailure, err)
+	}
+	syscall.CloseOnExec(fd)
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		syscall.Close(fd)
+		return -1, httperrors.NewTransportError(httperrors.SocketCreateFailure, err)
+	}
+
+	err = syscall.Connect(fd, sa)
+	if errors.Is(err, syscall.EINPROGRESS) {
+		if err = waitFd(ctx, kq, fd, syscall.EVFILT_WRITE, deadline); err == nil {
+			var soErr int
+			if soErr, err = syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_ERROR); err == nil && soErr != 0 {
+				err = syscall.Errno(soErr)
+			}
+		}
+	}
+	if err != nil {
+		syscall.Close(fd)
+		if isTimeout(err) || ctx.Err() != nil {
+			return -1, err
+		}
+		return -1, &net.OpError{Op: "dial", Net: "tcp", Err: err}
+	}
+
+	// Disable Nagle's algorithm for lower latency, like TcpTransport
+	if err := syscall.SetsockoptInt(fd, syscall.IPPROTO_TCP, syscall.TCP_NODELAY, 1); err != nil {
+		syscall.Close(fd)
+		return -1, httperrors.NewTransportError(httperrors.InitFailure, err)
+	}
+	return fd, nil
+}
+
+func sockaddrFor(ip net.IPAddr, port int) (syscall.Sockaddr, int, error) {
+	if ip4 := ip.IP.To4(); ip4 != nil {
+		sa := &syscall.SockaddrInet4{Port: port}
+		copy(sa.Addr[:], ip4)
+		return sa, syscall.AF_INET, nil
+	}
+
+	sa := &syscall.SockaddrInet6{Port: port}
+	copy(sa.Addr[:], ip.IP.To16())
+	if ip.Zone != "" {
+		ifi, err := net.InterfaceByName(ip.Zone)
+		if err != nil {
+			return nil, 0, err
+		}
+		sa.ZoneId = uint32(ifi.Index)
+	}
+	return sa, syscall.AF_INET6, nil
+}
+
+// waitFd blocks until fd is ready for the given kqueue filter. A non-zero
+// deadline bounds the wait; os.ErrDeadlineExceeded is returned once it passes.
+// If ctx is done first, its error is returned.
+func waitFd(ctx context.Context, kq, fd int, filter int, deadline time.Time) error {
+	changes := make([]syscall.Kevent_t, 1)
+	syscall.SetKevent(&changes[0], fd, filter, syscall.EV_ADD|syscall.EV_ONESHOT)
+	events := make([]syscall.Kevent_t, 1)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		wait := time.Duration(-1)
+		if !deadline.IsZero() {
+			wait = time.Until(deadline)
+			if wait <= 0 {
+				return os.ErrDeadlineExceeded
+			}
+		}
+		if ctx.Done() != nil && (wait < 0 || wait > kqueueCancelPoll) {
+			wait = kqueueCancelPoll
+		}
+		var timeout *syscall.Timespec
+		if wait >= 0 {
+			ts := syscall.NsecToTimespec(int64(wait))
+			timeout = &ts
+		}
+		n, err := syscall.Kevent(kq, changes, events, timeout)
+		if errors.Is(err, syscall.EINTR) || (err == nil && n == 0) {
+			// Interrupted, or a poll slice ended; re-check ctx and deadline
+			continue
+		}
+		return err
+	}
+}
+
+// Write sends data over the connection, waiting for writability as needed
+func (t *KqueueTransport) Write(buf []byte) (int, error) {
+	return t.WriteCtx(context.Background(), buf)
+}
+
+// WriteCtx is Write with cancellation
+func (t *KqueueTransport) WriteCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.fd < 0 {
+		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
+	}
+	if ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+
+	deadline := ioDeadline(ctx, t.Options.WriteTimeout)
+	written := 0
+	for written < len(buf) {
+		n, err := syscall.Write(t.fd, buf[written:])
+		if n > 0 {
+			written += n
+		}
+		switch {
+		case err == nil:
+		case errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR):
+			if err := waitFd(ctx, t.kq, t.fd, syscall.EVFILT_WRITE, deadline); err != nil {
+				if ctx.Err() != nil {
+					return written, contextError(ctx)
+				}
+				if isTimeout(err) {
+					return written, httperrors.NewTransportError(httperrors.Timeout, err)
+				}
+				return written, httperrors.NewTransportError(httperrors.SocketWriteFailure, err)
+			}
+		case isConnectionClosed(err):
+			return written, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+		default:
+			return written, httperrors.NewTransportError(httperrors.SocketWriteFailure, err)
+		}
+	}
+	return written, nil
+}
+
+// Read receives data from the connection, waiting for readability as needed
+func (t *KqueueTransport) Read(buf []byte) (int, error) {
+	return t.ReadCtx(context.Background(), buf)
+}
+
+// ReadCtx is Read with cancellation
+func (t *KqueueTransport) ReadCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.fd < 0 {
+		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
+	}
+	if ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+
+	deadline := ioDeadline(ctx, t.Options.ReadTimeout)
+	for {
+		n, err := syscall.Read(t.fd, buf)
+		switch {
+		case err == nil && n == 0 && len(buf) > 0:
+			return 0, httperrors.NewTransportError(httperrors.ConnectionClosed, nil)
+		case err == nil:
+			return n, nil
+		case errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR):
+			if err := waitFd(ctx, t.kq, t.fd, syscall.EVFILT_READ, deadline); err != nil {
+				if ctx.Err() != nil {
+					return 0, contextError(ctx)
+				}
+				if isTimeout(err) {
+					return 0, httperrors.NewTransportError(httperrors.Timeout, err)
+				}
+				return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+			}
+		case isConnectionClosed(err):
+			return 0, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+		default:
+			return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+		}
+	}
+}
+
+// Abort closes the connection with SO_LINGER set to zero, so the kernel
+// sends RST instead of shutting down gracefully
+func (t *KqueueTransport) Abort() error {
+	if t.fd < 0 {
+		return nil
+	}
+	linger := syscall.Linger{Onoff: 1, Linger: 0}
+	if err := syscall.SetsockoptLinger(t.fd, syscall.SOL_SOCKET, syscall.SO_LINGER, &linger); err != nil {
+		t.Close()
+		return httperrors.NewTransportError(httperrors.SocketCloseFailure, err)
+	}
+	return t.Close()
+}
+
+// Close closes the connection and its kqueue
+func (t *KqueueTransport) Close() error {
+	if t.fd < 0 {
+		return nil // Idempotent close
+	}
+
+	err := syscall.Close(t.fd)
+	syscall.Close(t.kq)
+	t.fd = -1
+	t.kq = -1
+
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.SocketCloseFailure, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package transport
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestKqueueTransport_ReadCtx_Cancel(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewKqueueTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := transport.ReadCtx(ctx, make([]byte, 16))
+	expectTransportError(t, err, httperrors.Cancelled)
+}
+
+func TestKqueueTransport_WriteCtx_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewKqueueTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := writeUntilBlocked(transport, ctx)
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestKqueueTransport_Connect_ClosesPrevious(t *testing.T) {
+	closed := make(chan struct{}, 2)
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+		closed <- struct{}{}
+	})
+	defer cleanup()
+
+	transport := NewKqueueTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("First connect failed: %v", err)
+	}
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Second connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected reconnecting to close the previous connection")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package transport
+
+import "syscall"
+
+// setLingerZero makes closing the socket send RST instead of FIN
+func setLingerZero(fd uintptr) {
+	linger := syscall.Linger{Onoff: 1, Linger: 0}
+	syscall.SetsockoptLinger(int(fd), syscall.SOL_SOCKET, syscall.SO_LINGER, &linger)
+}
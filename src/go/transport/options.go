@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"time"
+)
+
+// TransportOptions holds the timeouts enforced by the transports. The
+// simulated and C transports support only a subset; see their Options
+// fields. A zero duration means no limit. Timeouts fail with a Timeout
+// error.
+type TransportOptions struct {
+	// ConnectTimeout bounds name resolution, dialing and, for TLS, the handshake.
+	ConnectTimeout time.Duration
+	// ReadTimeout bounds each Read call, so a stalled server cannot block forever.
+	ReadTimeout time.Duration
+	// WriteTimeout bounds each Write call.
+	WriteTimeout time.Duration
+}
+
+// connectContext derives the context for a connect from ConnectTimeout
+func (o TransportOptions) connectContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.ConnectTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.ConnectTimeout)
+}
+
+// armRead sets the deadline for the next Read to ReadTimeout from now, or
+// ctx's deadline if that is sooner. A cancel racing with this may have had
+// its expired deadline overwritten, so ctx is checked again afterwards.
+func (o TransportOptions) armRead(ctx context.Context, conn net.Conn) error {
+	conn.SetReadDeadline(ioDeadline(ctx, o.ReadTimeout))
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	return nil
+}
+
+// armWrite is armRead for the next Write and WriteTimeout
+func (o TransportOptions) armWrite(ctx context.Context, conn net.Conn) error {
+	conn.SetWriteDeadline(ioDeadline(ctx, o.WriteTimeout))
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	return nil
+}
+
+// ioDeadline returns the earlier of timeout from now and ctx's deadline.
+// The zero time means neither applies.
+func ioDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
+
+// isTimeout reports whether err comes from an expired I/O deadline
+func isTimeout(err error) bool {
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/internal/testserver"
+)
+
+func TestTcpTransport_ReadTimeout_StalledServer(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, testserver.Script(
+		testserver.StallAfterHeaders([]byte("HTTP/1.1 200 OK\r\nContent-Length: 10\r\n\r\n"), 500*time.Millisecond),
+	))
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	transport.Options.ReadTimeout = 50 * time.Millisecond
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	buf := make([]byte, 1024)
+	if _, err := transport.Read(buf); err != nil {
+		t.Fatalf("Reading the headers failed: %v", err)
+	}
+	start := time.Now()
+	_, err := transport.Read(buf)
+	expectTransportError(t, err, httperrors.Timeout)
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("Read timed out after %v, expected about 50ms", elapsed)
+	}
+}
+
+func TestTcpTransport_ReadTimeout_AppliesPerRead(t *testing.T) {
+	// Each chunk arrives well within the timeout even though the whole
+	// response takes longer than it
+	body := []byte("0123456789")
+	host, port, cleanup := setupTcpTestServer(t, testserver.Script(
+		testserver.Chunked(body, 2, 30*time.Millisecond),
+	))
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	transport.Options.ReadTimeout = 100 * time.Millisecond
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	got := 0
+	buf := make([]byte, 16)
+	for got < len(body) {
+		n, err := transport.Read(buf)
+		if err != nil {
+			t.Fatalf("Read failed after %d bytes: %v", got, err)
+		}
+		got += n
+	}
+}
+
+func TestTcpTransport_WriteTimeout_PeerNotReading(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewTcpTransport()
+	transport.Options.WriteTimeout = 50 * time.Millisecond
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	// Large enough to fill both socket buffers
+	_, err := transport.Write(make([]byte, 64<<20))
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestTcpTransport_ConnectTimeout(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.Options.ConnectTimeout = 50 * time.Millisecond
+	transport.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	err := transport.Connect("example.invalid", 80)
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestTlsTransport_ConnectTimeout_CoversHandshake(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		conn.Read(make([]byte, 4096))
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{})
+	transport.Tcp.Options.ConnectTimeout = 50 * time.Millisecond
+	err := transport.Connect(host, port)
+	expectTransportError(t, err, httperrors.Timeout)
+}
+
+func TestUnixTransport_ReadTimeout(t *testing.T) {
+	path, cleanup := setupUnixTestServer(t, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	defer cleanup()
+
+	transport := NewUnixTransport()
+	transport.Options.ReadTimeout = 50 * time.Millisecond
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	_, err := transport.Read(make([]byte, 16))
+	expectTransportError(t, err, httperrors.Timeout)
+}
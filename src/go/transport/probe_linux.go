@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"errors"
+	"net"
+	"syscall"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+var errUnsolicitedData = errors.New("unsolicited data on idle connection")
+
+// probeConn checks an idle connection without blocking or consuming data.
+// A peeked EOF or a pending socket error means the peer has gone away;
+// unsolicited bytes on an idle HTTP/1.1 connection (typically a 408 sent
+// just before the server closed) mean it must not be reused either.
+func probeConn(conn net.Conn) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+	}
+
+	var (
+		n       int
+		peekErr error
+		soErr   int
+		optErr  error
+	)
+	buf := make([]byte, 1)
+	err = raw.Read(func(fd uintptr) bool {
+		soErr, optErr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_ERROR)
+		n, _, peekErr = syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		// Never ask the runtime poller to wait, this must stay non-blocking
+		return true
+	})
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+	}
+
+	switch {
+	case optErr != nil:
+		return httperrors.NewTransportError(httperrors.SocketReadFailure, optErr)
+	case soErr != 0:
+		return classifyProbeErrno(syscall.Errno(soErr))
+	case peekErr != nil:
+		if errors.Is(peekErr, syscall.EAGAIN) || errors.Is(peekErr, syscall.EWOULDBLOCK) {
+			return nil
+		}
+		return classifyProbeErrno(peekErr)
+	case n == 0:
+		return httperrors.NewTransportError(httperrors.ConnectionClosed, nil)
+	default:
+		return httperrors.NewTransportError(httperrors.SocketReadFailure, errUnsolicitedData)
+	}
+}
+
+func classifyProbeErrno(err error) error {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+	}
+	return httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+}
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestTcpTransport_Probe_Alive(t *testing.T) {
+	release := make(chan struct{})
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if err := transport.Probe(); err != nil {
+		t.Errorf("Expected idle connection to be alive, got %v", err)
+	}
+}
+
+func TestTcpTransport_Probe_PeerClosed(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		// Server immediately closes
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	// Give server time to close
+	time.Sleep(50 * time.Millisecond)
+
+	expectTransportError(t, transport.Probe(), httperrors.ConnectionClosed)
+}
+
+func TestTcpTransport_Probe_UnsolicitedData(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 408 Request Timeout\r\n\r\n"))
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	expectTransportError(t, transport.Probe(), httperrors.SocketReadFailure)
+
+	// Probing must not consume the data
+	buf := make([]byte, 8)
+	n, err := transport.Read(buf)
+	if err != nil || string(buf[:n]) != "HTTP/1.1" {
+		t.Errorf("Expected data to still be readable, got %q (%v)", buf[:n], err)
+	}
+}
+
+func TestTcpTransport_Probe_NoConnection(t *testing.T) {
+	expectTransportError(t, NewTcpTransport().Probe(), httperrors.ConnectionClosed)
+}
+
+func TestUnixTransport_Probe_Alive(t *testing.T) {
+	release := make(chan struct{})
+	path, cleanup := setupUnixTestServer(t, func(conn net.Conn) {
+		<-release
+	})
+	defer cleanup()
+	defer close(release)
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if err := transport.Probe(); err != nil {
+		t.Errorf("Expected idle connection to be alive, got %v", err)
+	}
+}
+
+func TestUnixTransport_Probe_PeerClosed(t *testing.T) {
+	path, cleanup := setupUnixTestServer(t, func(conn net.Conn) {
+		// Server immediately closes
+	})
+	defer cleanup()
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	expectTransportError(t, transport.Probe(), httperrors.ConnectionClosed)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package transport
+
+import "net"
+
+// probeConn has no cheap non-blocking check outside Linux and assumes the
+// connection is alive; a stale connection surfaces on the next Read instead
+func probeConn(conn net.Conn) error {
+	return nil
+}
@@ -0,0 +1,404 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+const (
+	defaultSimSegmentSize       = 1460
+	defaultSimRetransmitTimeout = 200 * time.Millisecond
+)
+
+// SimLink describes a simulated network path. The zero value is an
+// infinitely fast, lossless link.
+//
+// The simulated connection behaves like TCP: bytes are always delivered
+// intact and in order. Loss and reordering therefore show up as delay, a
+// lost segment costs a retransmission timeout and a reordered segment is
+// held back, and later segments queue behind either (head-of-line blocking).
+type SimLink struct {
+	// Latency is the one-way propagation delay; the RTT is twice this.
+	Latency time.Duration
+	// Bandwidth is the link rate in bytes per second per direction, 0 for unlimited.
+	Bandwidth int64
+	// Loss is the probability that a segment is lost and must be retransmitted.
+	// It must be in [0, 1); Connect fails with InitFailure otherwise.
+	Loss float64
+	// Reorder is the probability that a segment is delayed by ReorderDelay.
+	// It must be in [0, 1]; Connect fails with InitFailure otherwise.
+	Reorder float64
+	// ReorderDelay defaults to Latency.
+	ReorderDelay time.Duration
+	// RetransmitTimeout is the delay added per loss, 200ms by default.
+	RetransmitTimeout time.Duration
+	// SegmentSize is the payload size per segment, 1460 bytes by default.
+	SegmentSize int
+	// Seed makes loss and reordering decisions reproducible.
+	Seed int64
+	// RealTime makes Read sleep until simulated data arrives. By default
+	// simulated time only advances the clock reported by Elapsed, so tests
+	// run at full speed and produce identical timings on every run.
+	RealTime bool
+}
+
+// simSegment is a chunk of data together with its simulated arrival time
+type simSegment struct {
+	data    []byte
+	arrival time.Duration
+}
+
+// simDirection tracks one direction of the simulated link
+type simDirection struct {
+	rng         *rand.Rand
+	freeAt      time.Duration
+	lastArrival time.Duration
+}
+
+// schedule returns the arrival time of a segment of size bytes handed to the
+// link at departure, and advances the link state
+func (d *simDirection) schedule(link *SimLink, departure time.Duration, size int) time.Duration {
+	start := max(departure, d.freeAt)
+	var transmit time.Duration
+	if link.Bandwidth > 0 {
+		transmit = time.Duration(int64(size) * int64(time.Second) / link.Bandwidth)
+	}
+	d.freeAt = start + transmit
+
+	arrival := d.freeAt + link.Latency
+	for link.Loss > 0 && d.rng.Float64() < link.Loss {
+		arrival += link.RetransmitTimeout
+	}
+	if link.Reorder > 0 && d.rng.Float64() < link.Reorder {
+		arrival += link.ReorderDelay
+	}
+
+	// In-order delivery: nothing overtakes an earlier segment
+	arrival = max(arrival, d.lastArrival)
+	d.lastArrival = arrival
+	return arrival
+}
+
+// SimTransport implements the Transport interface over an in-process
+// simulated network. Connect starts the handler with the server end of the
+// connection; no sockets or privileges are involved.
+type SimTransport struct {
+	// Options holds the timeouts. Only ReadTimeout applies: it bounds both
+	// the real time spent waiting for a stalled peer and the simulated time
+	// until the next data arrives. Writes never block and connects are
+	// instant.
+	Options TransportOptions
+
+	link    SimLink
+	handler func(net.Conn)
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	conn     net.Conn
+	clock    time.Duration
+	up       simDirection
+	down     simDirection
+	outbound [][]byte
+	inbound  []simSegment
+	eof      bool
+	eofAt    time.Duration
+	writeErr error
+	closed   bool
+	// gen identifies the current connection so that pumps left over from a
+	// previous one cannot touch the state of the next
+	gen int
+}
+
+// NewSimTransport creates a SimTransport whose peer is served by handler
+func NewSimTransport(link SimLink, handler func(net.Conn)) *SimTransport {
+	if link.SegmentSize <= 0 {
+		link.SegmentSize = defaultSimSegmentSize
+	}
+	if link.RetransmitTimeout <= 0 {
+		link.RetransmitTimeout = defaultSimRetransmitTimeout
+	}
+	if link.ReorderDelay <= 0 {
+		link.ReorderDelay = link.Latency
+	}
+	t := &SimTransport{
+		link:    link,
+		handler: handler,
+	}
+	t.cond = sync.NewCond(&t.mu)
+	return t
+}
+
+// Connect starts the simulated peer. Host and port are ignored.
+// The TCP handshake costs one round trip of simulated time.
+func (t *SimTransport) Connect(host string, port uint16) error {
+	if t.handler == nil {
+		return httperrors.NewTransportError(httperrors.SocketConnectFailure, errors.New("no simulated peer"))
+	}
+	// A segment lost with certainty would be retransmitted forever
+	if !(t.link.Loss >= 0 && t.link.Loss < 1) {
+		return httperrors.NewTransportError(httperrors.InitFailure, fmt.Errorf("simulated loss %v outside [0, 1)", t.link.Loss))
+	}
+	if !(t.link.Reorder >= 0 && t.link.Reorder <= 1) {
+		return httperrors.NewTransportError(httperrors.InitFailure, fmt.Errorf("simulated reorder %v outside [0, 1]", t.link.Reorder))
+	}
+
+	// Closing the previous pipe ends its peer handler and pumps
+	t.Close()
+
+	client, server := net.Pipe()
+
+	t.mu.Lock()
+	// Separate generators keep each direction deterministic regardless of
+	// how the two goroutines interleave
+	t.up = simDirection{rng: rand.New(rand.NewSource(t.link.Seed))}
+	t.down = simDirection{rng: rand.New(rand.NewSource(t.link.Seed + 1))}
+	t.conn = client
+	t.clock = 2 * t.link.Latency
+	t.up.freeAt, t.up.lastArrival = t.clock, t.clock
+	t.down.freeAt, t.down.lastArrival = t.clock, t.clock
+	t.outbound, t.inbound = nil, nil
+	t.eof, t.writeErr, t.closed = false, nil, false
+	t.gen++
+	gen := t.gen
+	t.mu.Unlock()
+
+	go func() {
+		t.handler(server)
+		server.Close()
+	}()
+	go t.pumpOutbound(client, gen)
+	go t.pumpInbound(client, gen)
+	return nil
+}
+
+// pumpOutbound forwards written data to the peer. Write never blocks on the
+// peer reading, just like a socket with a send buffer.
+func (t *SimTransport) pumpOutbound(conn net.Conn, gen int) {
+	for {
+		t.mu.Lock()
+		for len(t.outbound) == 0 && !t.closed && t.gen == gen {
+			t.cond.Wait()
+		}
+		if t.closed || t.gen != gen {
+			t.mu.Unlock()
+			return
+		}
+		data := t.outbound[0]
+		t.outbound = t.outbound[1:]
+		t.mu.Unlock()
+
+		if _, err := conn.Write(data); err != nil {
+			t.mu.Lock()
+			if t.gen == gen {
+				t.writeErr = err
+			}
+			t.mu.Unlock()
+			return
+		}
+	}
+}
+
+// pumpInbound reads what the peer sends and schedules it on the downlink.
+// The peer cannot have responded before the request reached it, so data
+// departs no earlier than the last uplink arrival.
+func (t *SimTransport) pumpInbound(conn net.Conn, gen int) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := conn.Read(buf)
+
+		t.mu.Lock()
+		if t.gen != gen {
+			t.mu.Unlock()
+			return
+		}
+		departure := t.up.lastArrival
+		for data := buf[:n]; len(data) > 0; {
+			size := min(len(data), t.link.SegmentSize)
+			segment := simSegment{
+				data:    append([]byte(nil), data[:size]...),
+				arrival: t.down.schedule(&t.link, departure, size),
+			}
+			t.inbound = append(t.inbound, segment)
+			data = data[size:]
+		}
+		if err != nil {
+			t.eof = true
+			t.eofAt = max(departure+t.link.Latency, t.down.lastArrival)
+		}
+		t.cond.Broadcast()
+		t.mu.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ConnectCtx is Connect with cancellation. Connecting is instant, so only
+// a context that is already done makes it fail.
+func (t *SimTransport) ConnectCtx(ctx context.Context, host string, port uint16) error {
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	return t.Connect(host, port)
+}
+
+// WriteCtx is Write with cancellation. Writes never block, so only a
+// context that is already done makes it fail.
+func (t *SimTransport) WriteCtx(ctx context.Context, buf []byte) (int, error) {
+	if ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+	return t.Write(buf)
+}
+
+// Write sends data over the simulated link
+func (t *SimTransport) Write(buf []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
+	}
+	if t.writeErr != nil {
+		return 0, httperrors.NewTransportError(httperrors.ConnectionClosed, t.writeErr)
+	}
+
+	for data := buf; len(data) > 0; {
+		size := min(len(data), t.link.SegmentSize)
+		t.up.schedule(&t.link, t.clock, size)
+		data = data[size:]
+	}
+	t.outbound = append(t.outbound, append([]byte(nil), buf...))
+	t.cond.Broadcast()
+	return len(buf), nil
+}
+
+// Read receives data from the simulated link, advancing the simulated clock
+// to the arrival time of the returned data
+func (t *SimTransport) Read(buf []byte) (int, error) {
+	return t.ReadCtx(context.Background(), buf)
+}
+
+// ReadCtx is Read with cancellation. The context bounds the real time spent
+// waiting for the peer; it does not cut short simulated delays.
+func (t *SimTransport) ReadCtx(ctx context.Context, buf []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
+	}
+	if ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		t.mu.Lock()
+		t.cond.Broadcast()
+		t.mu.Unlock()
+	})
+	defer stop()
+
+	timeout := t.Options.ReadTimeout
+	expired := false
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			t.mu.Lock()
+			expired = true
+			t.cond.Broadcast()
+			t.mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+	for len(t.inbound) == 0 && !t.eof && !t.closed && !expired && ctx.Err() == nil {
+		t.cond.Wait()
+	}
+	if t.closed {
+		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, net.ErrClosed)
+	}
+	if len(t.inbound) == 0 && !t.eof && ctx.Err() != nil {
+		return 0, contextError(ctx)
+	}
+	if len(t.inbound) == 0 && !t.eof {
+		// The peer stalled in real time; the wait already took the timeout
+		t.clock += timeout
+		return 0, httperrors.NewTransportError(httperrors.Timeout, os.ErrDeadlineExceeded)
+	}
+
+	next := t.eofAt
+	if len(t.inbound) > 0 {
+		next = t.inbound[0].arrival
+	}
+	if timeout > 0 && next > t.clock+timeout {
+		t.advanceLocked(t.clock + timeout)
+		return 0, httperrors.NewTransportError(httperrors.Timeout, os.ErrDeadlineExceeded)
+	}
+	if len(t.inbound) == 0 {
+		t.advanceLocked(t.eofAt)
+		return 0, httperrors.NewTransportError(httperrors.ConnectionClosed, io.EOF)
+	}
+
+	t.advanceLocked(t.inbound[0].arrival)
+
+	n := 0
+	for len(t.inbound) > 0 && n < len(buf) && t.inbound[0].arrival <= t.clock {
+		segment := &t.inbound[0]
+		copied := copy(buf[n:], segment.data)
+		n += copied
+		segment.data = segment.data[copied:]
+		if len(segment.data) == 0 {
+			t.inbound = t.inbound[1:]
+		}
+	}
+	return n, nil
+}
+
+// advanceLocked moves the simulated clock forward to at, sleeping for the
+// difference in real-time mode. The lock is released while sleeping.
+func (t *SimTransport) advanceLocked(at time.Duration) {
+	if at <= t.clock {
+		return
+	}
+	if t.link.RealTime {
+		wait := at - t.clock
+		t.mu.Unlock()
+		time.Sleep(wait)
+		t.mu.Lock()
+	}
+	t.clock = max(t.clock, at)
+}
+
+// Elapsed returns the simulated time since Connect was called
+func (t *SimTransport) Elapsed() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.clock
+}
+
+// Close closes the simulated connection
+func (t *SimTransport) Close() error {
+	t.mu.Lock()
+	conn := t.conn
+	if conn == nil {
+		t.mu.Unlock()
+		return nil // Idempotent close
+	}
+	t.conn = nil
+	t.closed = true
+	t.cond.Broadcast()
+	t.mu.Unlock()
+
+	if err := conn.Close(); err != nil {
+		return httperrors.NewTransportError(httperrors.SocketCloseFailure, err)
+	}
+	return nil
+}
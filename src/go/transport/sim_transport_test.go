@@ -0,0 +1,251 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// echoHandler echoes everything back until the client closes
+func echoHandler(conn net.Conn) {
+	io.Copy(conn, conn)
+}
+
+// simRoundTrip writes payload and reads back len(payload) bytes
+func simRoundTrip(t *testing.T, tr *SimTransport, payload []byte) []byte {
+	t.Helper()
+
+	if _, err := tr.Write(payload); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	received := make([]byte, 0, len(payload))
+	buf := make([]byte, 4096)
+	for len(received) < len(payload) {
+		n, err := tr.Read(buf)
+		if err != nil {
+			t.Fatalf("Read failed after %d bytes: %v", len(received), err)
+		}
+		received = append(received, buf[:n]...)
+	}
+	return received
+}
+
+func TestSimTransport_Latency(t *testing.T) {
+	link := SimLink{Latency: 10 * time.Millisecond}
+	transport := NewSimTransport(link, echoHandler)
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if got := transport.Elapsed(); got != 20*time.Millisecond {
+		t.Errorf("Expected handshake to cost one RTT (20ms), got %v", got)
+	}
+
+	simRoundTrip(t, transport, []byte("ping"))
+	if got := transport.Elapsed(); got != 40*time.Millisecond {
+		t.Errorf("Expected one more RTT after the exchange (40ms), got %v", got)
+	}
+}
+
+func TestSimTransport_Bandwidth(t *testing.T) {
+	link := SimLink{Bandwidth: 1 << 20}
+	transport := NewSimTransport(link, echoHandler)
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	payload := bytes.Repeat([]byte("x"), 256<<10)
+	simRoundTrip(t, transport, payload)
+
+	// 256 KiB up and back at 1 MiB/s; the downlink can start as soon as the
+	// last uplink byte arrives, so expect roughly 2 x 250ms
+	got := transport.Elapsed()
+	if got < 450*time.Millisecond || got > 550*time.Millisecond {
+		t.Errorf("Expected about 500ms of simulated transfer time, got %v", got)
+	}
+}
+
+func TestSimTransport_LossAndReorderKeepDataIntact(t *testing.T) {
+	link := SimLink{
+		Latency:     5 * time.Millisecond,
+		Loss:        0.2,
+		Reorder:     0.2,
+		SegmentSize: 100,
+		Seed:        42,
+	}
+	transport := NewSimTransport(link, echoHandler)
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	payload := make([]byte, 10000)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	if got := simRoundTrip(t, transport, payload); !bytes.Equal(got, payload) {
+		t.Fatal("Expected payload to survive loss and reordering intact")
+	}
+
+	lossless := NewSimTransport(SimLink{Latency: 5 * time.Millisecond, SegmentSize: 100}, echoHandler)
+	if err := lossless.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer lossless.Close()
+	simRoundTrip(t, lossless, payload)
+
+	if transport.Elapsed() <= lossless.Elapsed() {
+		t.Errorf("Expected loss to cost time: %v vs lossless %v", transport.Elapsed(), lossless.Elapsed())
+	}
+}
+
+func TestSimTransport_Deterministic(t *testing.T) {
+	run := func() time.Duration {
+		link := SimLink{Latency: time.Millisecond, Loss: 0.1, Reorder: 0.1, SegmentSize: 64, Seed: 7}
+		transport := NewSimTransport(link, echoHandler)
+		if err := transport.Connect("sim", 0); err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		defer transport.Close()
+		for i := 0; i < 5; i++ {
+			simRoundTrip(t, transport, bytes.Repeat([]byte{byte(i)}, 1000))
+		}
+		return transport.Elapsed()
+	}
+
+	first := run()
+	for i := 0; i < 3; i++ {
+		if got := run(); got != first {
+			t.Fatalf("Expected identical simulated time on every run, got %v and %v", first, got)
+		}
+	}
+}
+
+func TestSimTransport_ReadTimeout_StalledPeer(t *testing.T) {
+	transport := NewSimTransport(SimLink{}, func(conn net.Conn) {
+		conn.Read(make([]byte, 1))
+	})
+	transport.Options.ReadTimeout = 50 * time.Millisecond
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	_, err := transport.Read(make([]byte, 16))
+	expectTransportError(t, err, httperrors.Timeout)
+	if got := transport.Elapsed(); got != 50*time.Millisecond {
+		t.Errorf("Expected the timeout to advance the clock by 50ms, got %v", got)
+	}
+}
+
+func TestSimTransport_ReadTimeout_SimulatedLatency(t *testing.T) {
+	transport := NewSimTransport(SimLink{Latency: time.Second}, echoHandler)
+	transport.Options.ReadTimeout = 500 * time.Millisecond
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if _, err := transport.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// The echo arrives 2s after the handshake, so the first three reads time out
+	buf := make([]byte, 16)
+	for i := 0; i < 3; i++ {
+		_, err := transport.Read(buf)
+		expectTransportError(t, err, httperrors.Timeout)
+	}
+	n, err := transport.Read(buf)
+	if err != nil || string(buf[:n]) != "ping" {
+		t.Fatalf("Expected the echo after the timeouts, got %q, %v", buf[:n], err)
+	}
+	if got := transport.Elapsed(); got != 4*time.Second {
+		t.Errorf("Expected 4s of simulated time, got %v", got)
+	}
+}
+
+func TestSimTransport_Read_PeerClosed(t *testing.T) {
+	transport := NewSimTransport(SimLink{Latency: time.Millisecond}, func(conn net.Conn) {
+		conn.Write([]byte("bye"))
+	})
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	buf := make([]byte, 16)
+	n, err := transport.Read(buf)
+	if err != nil || string(buf[:n]) != "bye" {
+		t.Fatalf("Expected \"bye\", got %q (%v)", buf[:n], err)
+	}
+
+	_, err = transport.Read(buf)
+	expectTransportError(t, err, httperrors.ConnectionClosed)
+}
+
+func TestSimTransport_Connect_NoPeer(t *testing.T) {
+	err := NewSimTransport(SimLink{}, nil).Connect("sim", 0)
+	expectTransportError(t, err, httperrors.SocketConnectFailure)
+}
+
+func TestSimTransport_Connect_InvalidLoss(t *testing.T) {
+	for _, loss := range []float64{-0.1, 1, 1.5, math.NaN()} {
+		err := NewSimTransport(SimLink{Loss: loss}, echoHandler).Connect("sim", 0)
+		expectTransportError(t, err, httperrors.InitFailure)
+	}
+}
+
+func TestSimTransport_Connect_InvalidReorder(t *testing.T) {
+	for _, reorder := range []float64{-0.1, 1.5, math.NaN()} {
+		err := NewSimTransport(SimLink{Reorder: reorder}, echoHandler).Connect("sim", 0)
+		expectTransportError(t, err, httperrors.InitFailure)
+	}
+}
+
+func TestSimTransport_Connect_ClosesPrevious(t *testing.T) {
+	done := make(chan struct{}, 2)
+	transport := NewSimTransport(SimLink{}, func(conn net.Conn) {
+		echoHandler(conn)
+		done <- struct{}{}
+	})
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("First connect failed: %v", err)
+	}
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Second connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected reconnecting to end the previous peer handler")
+	}
+
+	if got := simRoundTrip(t, transport, []byte("again")); string(got) != "again" {
+		t.Errorf("Expected \"again\", got %q", got)
+	}
+}
+
+func TestSimTransport_Close_Idempotent(t *testing.T) {
+	transport := NewSimTransport(SimLink{}, echoHandler)
+	if err := transport.Connect("sim", 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Errorf("First close failed: %v", err)
+	}
+	if err := transport.Close(); err != nil {
+		t.Errorf("Second close failed: %v", err)
+	}
+
+	_, err := transport.Read(make([]byte, 1))
+	expectTransportError(t, err, httperrors.SocketReadFailure)
+}
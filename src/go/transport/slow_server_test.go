@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+	"github.com/nczempin/0004_std_lib_http_client/httpgo/internal/testserver"
+)
+
+const slowResponse = "HTTP/1.1 200 OK\r\nContent-Length: 11\r\n\r\nhello world"
+
+// readUntilClosed reads until the peer closes, returning everything received
+// and the number of Read calls that returned data
+func readUntilClosed(t *testing.T, tr Transport) ([]byte, int) {
+	t.Helper()
+
+	var received bytes.Buffer
+	reads := 0
+	buf := make([]byte, 1024)
+	for {
+		n, err := tr.Read(buf)
+		if n > 0 {
+			received.Write(buf[:n])
+			reads++
+		}
+		if err != nil {
+			httpErr, ok := err.(*httperrors.Error)
+			if !ok || httpErr.TransportErr == nil || *httpErr.TransportErr != httperrors.ConnectionClosed {
+				t.Fatalf("Expected ConnectionClosed at end of stream, got %v", err)
+			}
+			return received.Bytes(), reads
+		}
+	}
+}
+
+func TestTcpTransport_Read_Dribbled(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, testserver.Script(
+		testserver.Dribble([]byte(slowResponse), time.Millisecond),
+	))
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	received, reads := readUntilClosed(t, transport)
+	if string(received) != slowResponse {
+		t.Errorf("Expected %q, got %q", slowResponse, received)
+	}
+	if reads < 2 {
+		t.Errorf("Expected the response to arrive over several reads, got %d", reads)
+	}
+}
+
+func TestTcpTransport_Read_SplitAcrossPackets(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, testserver.Script(
+		testserver.Chunked([]byte(slowResponse), 7, 5*time.Millisecond),
+	))
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	received, _ := readUntilClosed(t, transport)
+	if string(received) != slowResponse {
+		t.Errorf("Expected %q, got %q", slowResponse, received)
+	}
+}
+
+func TestTcpTransport_Read_StallAfterHeaders(t *testing.T) {
+	const stall = 100 * time.Millisecond
+	host, port, cleanup := setupTcpTestServer(t, testserver.Script(
+		testserver.ReadRequestHead(),
+		testserver.StallAfterHeaders([]byte(slowResponse), stall),
+	))
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if _, err := transport.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	n, err := transport.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !bytes.HasSuffix(buf[:n], []byte("\r\n\r\n")) {
+		t.Fatalf("Expected only the header section first, got %q", buf[:n])
+	}
+
+	start := time.Now()
+	n, err = transport.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(buf[:n]) != "hello world" {
+		t.Errorf("Expected body, got %q", buf[:n])
+	}
+	if waited := time.Since(start); waited < stall/2 {
+		t.Errorf("Expected body read to wait for the stall, waited %v", waited)
+	}
+}
+
+func TestUnixTransport_Read_Dribbled(t *testing.T) {
+	path, cleanup := setupUnixTestServer(t, testserver.Script(
+		testserver.Dribble([]byte(slowResponse), time.Millisecond),
+	))
+	defer cleanup()
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	received, reads := readUntilClosed(t, transport)
+	if string(received) != slowResponse {
+		t.Errorf("Expected %q, got %q", slowResponse, received)
+	}
+	if reads < 2 {
+		t.Errorf("Expected the response to arrive over several reads, got %d", reads)
+	}
+}
+
+func TestUnixTransport_Read_AbruptClose(t *testing.T) {
+	partial := slowResponse[:len(slowResponse)-5]
+	path, cleanup := setupUnixTestServer(t, testserver.Script(
+		testserver.Chunked([]byte(partial), 4, time.Millisecond),
+		testserver.Close(),
+	))
+	defer cleanup()
+
+	transport := NewUnixTransport()
+	if err := transport.Connect(path, 0); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	received, _ := readUntilClosed(t, transport)
+	if string(received) != partial {
+		t.Errorf("Expected %q before the close, got %q", partial, received)
+	}
+}
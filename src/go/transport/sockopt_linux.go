@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// ipLocalPortRange is IP_LOCAL_PORT_RANGE from linux/in.h, which the syscall
+// package does not define
+const ipLocalPortRange = 51
+
+// portRangeSupport probes once whether the kernel knows IP_LOCAL_PORT_RANGE;
+// replaced in tests
+var portRangeSupport = sync.OnceValue(probePortRange)
+
+// newDialer returns a dialer that applies the local port range and
+// SO_REUSEADDR to each socket before it connects
+func newDialer(portRange [2]uint16, reuseAddr bool) (*net.Dialer, error) {
+	if portRange != [2]uint16{} && (portRange[0] == 0 || portRange[0] > portRange[1]) {
+		return nil, fmt.Errorf("invalid local port range %d-%d", portRange[0], portRange[1])
+	}
+
+	if portRange != [2]uint16{} {
+		if err := portRangeSupport(); err != nil {
+			return nil, err
+		}
+	}
+
+	dialer := &net.Dialer{}
+	if portRange == [2]uint16{} && !reuseAddr {
+		return dialer, nil
+	}
+
+	dialer.Control = func(network, address string, c syscall.RawConn) error {
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if reuseAddr {
+				sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+				if sockErr != nil {
+					return
+				}
+			}
+			if portRange != [2]uint16{} {
+				// The low port is in the lower 16 bits, the high port in the upper 16
+				value := int(portRange[0]) | int(portRange[1])<<16
+				sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, ipLocalPortRange, value)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+	return dialer, nil
+}
+
+// probePortRange sets IP_LOCAL_PORT_RANGE on a throwaway socket. Kernels
+// older than 6.3 reject the option with ENOPROTOOPT, which is reported as
+// errors.ErrUnsupported; other failures are left for the dial to report.
+func probePortRange() error {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil
+	}
+	defer syscall.Close(fd)
+
+	// A zero value selects the system-wide range
+	err = syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, ipLocalPortRange, 0)
+	if errors.Is(err, syscall.ENOPROTOOPT) {
+		return unsupportedPortRange(err)
+	}
+	return nil
+}
+
+func unsupportedPortRange(err error) error {
+	return fmt.Errorf("local port range needs Linux 6.3 or later: %w (%w)", errors.ErrUnsupported, err)
+}
@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// freeLocalPort returns a TCP port that nothing is bound to right now
+func freeLocalPort(t *testing.T) uint16 {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer l.Close()
+	return uint16(l.Addr().(*net.TCPAddr).Port)
+}
+
+// skipIfNoPortRange skips on kernels older than 6.3, which lack IP_LOCAL_PORT_RANGE
+func skipIfNoPortRange(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("IP_LOCAL_PORT_RANGE not supported by this kernel")
+	}
+}
+
+func TestTcpTransport_LocalPortRange(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		buf := make([]byte, 1)
+		conn.Read(buf)
+	})
+	defer cleanup()
+
+	local := freeLocalPort(t)
+	transport := NewTcpTransport()
+	transport.LocalPortRange = [2]uint16{local, local}
+	transport.ReuseAddr = true
+	err := transport.Connect(host, port)
+	skipIfNoPortRange(t, err)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if got := transport.conn.LocalAddr().(*net.TCPAddr).Port; got != int(local) {
+		t.Errorf("Expected local port %d, got %d", local, got)
+	}
+}
+
+func TestTcpTransport_LocalPortRange_Exhausted(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		buf := make([]byte, 1)
+		conn.Read(buf)
+	})
+	defer cleanup()
+
+	// A single-port range allows exactly one connection to a given peer
+	local := freeLocalPort(t)
+	first := NewTcpTransport()
+	first.Health = NewAddrHealth(DefaultFailedAddrTTL)
+	first.LocalPortRange = [2]uint16{local, local}
+	err := first.Connect(host, port)
+	skipIfNoPortRange(t, err)
+	if err != nil {
+		t.Fatalf("First connect failed: %v", err)
+	}
+	defer first.Close()
+
+	second := NewTcpTransport()
+	second.Health = first.Health
+	second.LocalPortRange = first.LocalPortRange
+	err = second.Connect(host, port)
+	expectTransportError(t, err, httperrors.LocalAddrUnavailable)
+
+	if second.Health.RecentlyFailed(net.JoinHostPort(host, strconv.Itoa(int(port)))) {
+		t.Error("Expected local port exhaustion not to mark the remote address as failed")
+	}
+}
+
+func TestTcpTransport_LocalPortRange_Invalid(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.LocalPortRange = [2]uint16{5000, 4000}
+	err := transport.Connect("127.0.0.1", 80)
+	expectTransportError(t, err, httperrors.InitFailure)
+}
+
+func TestTcpTransport_LocalPortRange_Unsupported(t *testing.T) {
+	saved := portRangeSupport
+	defer func() { portRangeSupport = saved }()
+	portRangeSupport = func() error { return unsupportedPortRange(syscall.ENOPROTOOPT) }
+
+	transport := NewTcpTransport()
+	transport.LocalPortRange = [2]uint16{40000, 40100}
+	err := transport.Connect("127.0.0.1", 80)
+	expectTransportError(t, err, httperrors.InitFailure)
+	if !errors.Is(err, errors.ErrUnsupported) || !errors.Is(err, syscall.ENOPROTOOPT) {
+		t.Errorf("Expected errors.ErrUnsupported wrapping ENOPROTOOPT, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package transport
+
+import (
+	"errors"
+	"net"
+)
+
+// newDialer returns a plain dialer; local port ranges and SO_REUSEADDR are
+// only implemented on Linux
+func newDialer(portRange [2]uint16, reuseAddr bool) (*net.Dialer, error) {
+	if portRange != [2]uint16{} || reuseAddr {
+		return nil, errors.ErrUnsupported
+	}
+	return &net.Dialer{}, nil
+}
@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// SrvTarget is a single host:port target obtained from a DNS SRV record
+type SrvTarget struct {
+	Host     string
+	Port     uint16
+	Priority uint16
+	Weight   uint16
+}
+
+// ResolveSRV looks up _service._tcp.name and returns the targets in the
+// order RFC 2782 prescribes for connection attempts: ascending priority,
+// and weighted random selection among targets of equal priority.
+func ResolveSRV(service, name string) ([]SrvTarget, error) {
+	return resolveSRV(context.Background(), net.DefaultResolver.LookupSRV, rand.Float64, service, name)
+}
+
+type lookupSRVFunc func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+func resolveSRV(ctx context.Context, lookup lookupSRVFunc, random func() float64, service, name string) ([]SrvTarget, error) {
+	_, records, err := lookup(ctx, service, "tcp", name)
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+	if err != nil {
+		return nil, httperrors.NewTransportError(httperrors.DnsFailure, err)
+	}
+
+	// A single record with target "." means the service is decidedly not
+	// available at this domain
+	if len(records) == 1 && strings.TrimSuffix(records[0].Target, ".") == "" {
+		return nil, httperrors.NewTransportError(httperrors.DnsFailure,
+			errors.New("service not available: SRV target is \".\""))
+	}
+	if len(records) == 0 {
+		return nil, httperrors.NewTransportError(httperrors.DnsFailure, errors.New("no SRV records"))
+	}
+
+	return orderSRV(records, random), nil
+}
+
+// orderSRV sorts records by priority and, within each priority, repeatedly
+// selects a record by weight as RFC 2782 describes: zero-weight records go
+// first, a draw is taken uniformly from [0, total weight], and the first
+// record whose running weight sum reaches the draw is picked. Zero-weight
+// records therefore only win a draw of 0, or when every weight is 0.
+func orderSRV(records []*net.SRV, random func() float64) []SrvTarget {
+	sorted := append([]*net.SRV(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority < sorted[j].Priority
+		}
+		return sorted[i].Weight == 0 && sorted[j].Weight != 0
+	})
+
+	targets := make([]SrvTarget, 0, len(sorted))
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Priority == sorted[start].Priority {
+			end++
+		}
+
+		group := sorted[start:end]
+		for len(group) > 0 {
+			total := 0
+			for _, r := range group {
+				total += int(r.Weight)
+			}
+			draw := random() * float64(total)
+			chosen := len(group) - 1
+			sum := 0
+			for i, r := range group {
+				sum += int(r.Weight)
+				if float64(sum) >= draw {
+					chosen = i
+					break
+				}
+			}
+
+			r := group[chosen]
+			targets = append(targets, SrvTarget{
+				Host:     strings.TrimSuffix(r.Target, "."),
+				Port:     r.Port,
+				Priority: r.Priority,
+				Weight:   r.Weight,
+			})
+			group = append(group[:chosen:chosen], group[chosen+1:]...)
+		}
+		start = end
+	}
+	return targets
+}
+
+// SrvError describes why ConnectSRV failed after trying every target. It is
+// the underlying error of the returned TransportError and can be retrieved
+// with errors.As; the per-target errors usually wrap a *DialError.
+type SrvError struct {
+	Service string
+	Name    string
+	// Targets lists every target tried, in order.
+	Targets []SrvTarget
+	// Errors holds the error for each entry of Targets.
+	Errors []error
+}
+
+func (e *SrvError) Error() string {
+	parts := make([]string, len(e.Targets))
+	for i, target := range e.Targets {
+		parts[i] = fmt.Sprintf("%s: %v", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))), e.Errors[i])
+	}
+	return fmt.Sprintf("srv _%s._tcp.%s: %d target(s) failed: %s",
+		e.Service, e.Name, len(e.Targets), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the individual targets
+func (e *SrvError) Unwrap() []error {
+	return e.Errors
+}
+
+// ConnectSRV resolves _service._tcp.name and connects to the first target
+// that accepts a connection, failing over in RFC 2782 order. If every target
+// fails, the error carries the code of the last failure and wraps a
+// *SrvError listing all of them.
+func (t *TcpTransport) ConnectSRV(service, name string) error {
+	return t.ConnectSRVCtx(context.Background(), service, name)
+}
+
+// ConnectSRVCtx is ConnectSRV with cancellation. ConnectTimeout bounds the
+// SRV lookup and all connection attempts together.
+func (t *TcpTransport) ConnectSRVCtx(ctx context.Context, service, name string) error {
+	ctx, cancel := t.Options.connectContext(ctx)
+	defer cancel()
+
+	lookup := t.lookupSRV
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupSRV
+	}
+	targets, err := resolveSRV(ctx, lookup, rand.Float64, service, name)
+	if err != nil {
+		return err
+	}
+
+	srvErr := &SrvError{Service: service, Name: name}
+	code := httperrors.SocketConnectFailure
+	for _, target := range targets {
+		err := t.ConnectCtx(ctx, target.Host, target.Port)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		var tErr *httperrors.Error
+		if errors.As(err, &tErr) && tErr.TransportErr != nil {
+			code = *tErr.TransportErr
+		}
+		srvErr.Targets = append(srvErr.Targets, target)
+		srvErr.Errors = append(srvErr.Errors, err)
+	}
+	return httperrors.NewTransportError(code, srvErr)
+}
@@ -0,0 +1,190 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func fakeSRV(records ...*net.SRV) lookupSRVFunc {
+	return func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		return "", records, nil
+	}
+}
+
+// sequence returns the given values in turn as a deterministic random source
+func sequence(values ...float64) func() float64 {
+	return func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+}
+
+func TestOrderSRV_PriorityThenWeight(t *testing.T) {
+	records := []*net.SRV{
+		{Target: "backup.example.", Port: 80, Priority: 20, Weight: 1},
+		{Target: "a.example.", Port: 8080, Priority: 10, Weight: 1},
+		{Target: "b.example.", Port: 8081, Priority: 10, Weight: 3},
+	}
+
+	// 0.5 * total weight 4 = 2; the running sums are a=1 and b=4, so b wins
+	targets := orderSRV(records, sequence(0.5, 0.0, 0.0))
+
+	want := []string{"b.example", "a.example", "backup.example"}
+	if len(targets) != len(want) {
+		t.Fatalf("Expected %d targets, got %d", len(want), len(targets))
+	}
+	for i, host := range want {
+		if targets[i].Host != host {
+			t.Errorf("Position %d: expected %s, got %s", i, host, targets[i].Host)
+		}
+	}
+	if targets[0].Port != 8081 {
+		t.Errorf("Expected port 8081, got %d", targets[0].Port)
+	}
+}
+
+func TestOrderSRV_WeightDistribution(t *testing.T) {
+	records := []*net.SRV{
+		{Target: "light.", Priority: 1, Weight: 1},
+		{Target: "heavy.", Priority: 1, Weight: 9},
+	}
+
+	heavyFirst := 0
+	const runs = 100
+	for i := 0; i < runs; i++ {
+		// Evenly spaced samples across [0,1)
+		targets := orderSRV(records, sequence(float64(i)/runs, 0))
+		if targets[0].Host == "heavy" {
+			heavyFirst++
+		}
+	}
+	// light wins draws in [0,1] of [0,10], i.e. 11 of the 100 samples
+	if heavyFirst != 89 {
+		t.Errorf("Expected heavy target first in 89%% of runs, got %d%%", heavyFirst)
+	}
+}
+
+func TestOrderSRV_ZeroWeight(t *testing.T) {
+	records := []*net.SRV{
+		{Target: "weighted.", Priority: 1, Weight: 1},
+		{Target: "zero.", Priority: 1, Weight: 0},
+	}
+
+	zeroFirst := 0
+	const runs = 100
+	for i := 0; i < runs; i++ {
+		targets := orderSRV(records, sequence(float64(i)/runs, 0))
+		if targets[0].Host == "zero" {
+			zeroFirst++
+		}
+	}
+	// Only a draw of exactly 0 selects the zero-weight record
+	if zeroFirst != 1 {
+		t.Errorf("Expected zero-weight target first in 1 run, got %d", zeroFirst)
+	}
+
+	// With every weight 0 the records keep their order
+	allZero := []*net.SRV{
+		{Target: "a.", Priority: 1},
+		{Target: "b.", Priority: 1},
+	}
+	targets := orderSRV(allZero, sequence(0.7, 0.7))
+	if targets[0].Host != "a" || targets[1].Host != "b" {
+		t.Errorf("Expected a, b; got %s, %s", targets[0].Host, targets[1].Host)
+	}
+}
+
+func TestResolveSRV_ServiceNotAvailable(t *testing.T) {
+	_, err := resolveSRV(context.Background(), fakeSRV(&net.SRV{Target: "."}), sequence(0), "http", "example.com")
+	expectTransportError(t, err, httperrors.DnsFailure)
+}
+
+func TestResolveSRV_LookupFailure(t *testing.T) {
+	failing := func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	_, err := resolveSRV(context.Background(), failing, sequence(0), "http", "example.com")
+	expectTransportError(t, err, httperrors.DnsFailure)
+
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		t.Errorf("Expected underlying *net.DNSError, got %v", err)
+	}
+}
+
+func TestTcpTransport_ConnectSRV_FailsOver(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	transport.Health = NewAddrHealth(0)
+	transport.lookupSRV = fakeSRV(
+		// Nothing listens on 127.0.0.2, so the preferred target is refused
+		&net.SRV{Target: "127.0.0.2.", Port: port, Priority: 1, Weight: 1},
+		&net.SRV{Target: host + ".", Port: port, Priority: 2, Weight: 1},
+	)
+
+	if err := transport.ConnectSRV("http", "example.com"); err != nil {
+		t.Fatalf("ConnectSRV failed: %v", err)
+	}
+	defer transport.Close()
+
+	if transport.conn.RemoteAddr().(*net.TCPAddr).IP.String() != host {
+		t.Errorf("Expected connection to fallback target %s, got %v", host, transport.conn.RemoteAddr())
+	}
+}
+
+func TestTcpTransport_ConnectSRV_AllTargetsFail(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.Health = NewAddrHealth(0)
+	transport.lookupSRV = fakeSRV(
+		&net.SRV{Target: "127.0.0.2.", Port: 65531, Priority: 1, Weight: 1},
+		&net.SRV{Target: "127.0.0.3.", Port: 65531, Priority: 2, Weight: 1},
+	)
+
+	err := transport.ConnectSRV("http", "example.com")
+	expectTransportError(t, err, httperrors.SocketConnectFailure)
+
+	var srvErr *SrvError
+	if !errors.As(err, &srvErr) {
+		t.Fatalf("Expected *SrvError, got %v", err)
+	}
+	if len(srvErr.Targets) != 2 || len(srvErr.Errors) != 2 {
+		t.Fatalf("Expected 2 failed targets, got %d", len(srvErr.Targets))
+	}
+	for i, want := range []string{"127.0.0.2", "127.0.0.3"} {
+		if srvErr.Targets[i].Host != want {
+			t.Errorf("Target %d: expected %s, got %s", i, want, srvErr.Targets[i].Host)
+		}
+		var dialErr *DialError
+		if !errors.As(srvErr.Errors[i], &dialErr) {
+			t.Errorf("Target %d: expected a *DialError, got %v", i, srvErr.Errors[i])
+		}
+	}
+	if !isConnectionRefused(err) {
+		t.Error("Expected errors.Is to reach the per-attempt errno")
+	}
+}
+
+func TestTcpTransport_ConnectSRVCtx_HungLookup(t *testing.T) {
+	transport := NewTcpTransport()
+	transport.Options.ConnectTimeout = 50 * time.Millisecond
+	transport.lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		<-ctx.Done()
+		return "", nil, ctx.Err()
+	}
+
+	err := transport.ConnectSRVCtx(context.Background(), "http", "example.com")
+	expectTransportError(t, err, httperrors.Timeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = transport.ConnectSRVCtx(ctx, "http", "example.com")
+	expectTransportError(t, err, httperrors.Cancelled)
+}
@@ -0,0 +1,43 @@
+package transport
+
+import "time"
+
+// TcpInfo is a snapshot of the kernel's TCP statistics for a connection.
+// Fields the running kernel does not report are left zero.
+type TcpInfo struct {
+	// Rtt is the smoothed round-trip time.
+	Rtt time.Duration
+	// RttVar is the round-trip time variance.
+	RttVar time.Duration
+	// MinRtt is the minimum round-trip time observed.
+	MinRtt time.Duration
+	// Rto is the current retransmission timeout.
+	Rto time.Duration
+	// SndCwnd is the congestion window in segments.
+	SndCwnd uint32
+	// SndMss is the sender's maximum segment size in bytes.
+	SndMss uint32
+	// Retransmits is the total number of retransmitted segments.
+	Retransmits uint32
+	// Lost is the number of segments currently considered lost.
+	Lost uint32
+	// SegsOut and SegsIn count segments sent and received.
+	SegsOut uint32
+	SegsIn  uint32
+	// BytesAcked and BytesReceived count payload bytes.
+	BytesAcked    uint64
+	BytesReceived uint64
+	// DeliveryRate is the most recent goodput estimate in bytes per second.
+	DeliveryRate uint64
+}
+
+// Info returns current kernel statistics for the TCP connection
+func (t *TcpTransport) Info() (*TcpInfo, error) {
+	return tcpInfo(t.conn)
+}
+
+// FinalInfo returns the statistics captured when the connection was last
+// closed, or nil if none were captured (e.g. on platforms without TCP_INFO)
+func (t *TcpTransport) FinalInfo() *TcpInfo {
+	return t.finalInfo
+}
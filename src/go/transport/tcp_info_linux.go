@@ -0,0 +1,81 @@
+//go:build linux && !386
+
+// linux/386 has no getsockopt syscall number; sockets go through socketcall
+// there, so it uses the unsupported fallback.
+
+package transport
+
+import (
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// rawTcpInfo mirrors struct tcp_info from linux/tcp.h up to tcpi_delivery_rate.
+// syscall.TCPInfo stops at tcpi_total_retrans, which predates the counters
+// that are most useful for comparing transports.
+type rawTcpInfo struct {
+	syscall.TCPInfo
+	PacingRate    uint64
+	MaxPacingRate uint64
+	BytesAcked    uint64
+	BytesReceived uint64
+	SegsOut       uint32
+	SegsIn        uint32
+	NotsentBytes  uint32
+	MinRtt        uint32
+	DataSegsIn    uint32
+	DataSegsOut   uint32
+	DeliveryRate  uint64
+}
+
+func tcpInfo(conn net.Conn) (*TcpInfo, error) {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok || tcpConn == nil {
+		return nil, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
+	}
+	raw, err := tcpConn.SyscallConn()
+	if err != nil {
+		return nil, httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+	}
+
+	var info rawTcpInfo
+	size := uint32(unsafe.Sizeof(info))
+	var sysErr error
+	err = raw.Control(func(fd uintptr) {
+		_, _, errno := syscall.Syscall6(syscall.SYS_GETSOCKOPT, fd,
+			syscall.IPPROTO_TCP, syscall.TCP_INFO,
+			uintptr(unsafe.Pointer(&info)), uintptr(unsafe.Pointer(&size)), 0)
+		if errno != 0 {
+			sysErr = errno
+		}
+	})
+	if err == nil {
+		err = sysErr
+	}
+	if err != nil {
+		return nil, httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+	}
+
+	// Older kernels return a shorter struct; whatever they did not fill in
+	// stays zero because info was zero-initialized
+	usec := func(v uint32) time.Duration { return time.Duration(v) * time.Microsecond }
+	return &TcpInfo{
+		Rtt:           usec(info.Rtt),
+		RttVar:        usec(info.Rttvar),
+		MinRtt:        usec(info.MinRtt),
+		Rto:           usec(info.Rto),
+		SndCwnd:       info.Snd_cwnd,
+		SndMss:        info.Snd_mss,
+		Retransmits:   info.Total_retrans,
+		Lost:          info.Lost,
+		SegsOut:       info.SegsOut,
+		SegsIn:        info.SegsIn,
+		BytesAcked:    info.BytesAcked,
+		BytesReceived: info.BytesReceived,
+		DeliveryRate:  info.DeliveryRate,
+	}, nil
+}
@@ -0,0 +1,70 @@
+//go:build linux && !386
+
+package transport
+
+import (
+	"net"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestTcpTransport_Info(t *testing.T) {
+	payload := []byte("hello tcp info")
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		buf := make([]byte, len(payload))
+		conn.Read(buf)
+		conn.Write(buf)
+	})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if _, err := transport.Write(payload); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	buf := make([]byte, len(payload))
+	if _, err := transport.Read(buf); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	info, err := transport.Info()
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if info.SndMss == 0 {
+		t.Error("Expected a non-zero MSS on an established connection")
+	}
+	if info.Rtt < 0 {
+		t.Errorf("Unexpected RTT %v", info.Rtt)
+	}
+}
+
+func TestTcpTransport_FinalInfo(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {})
+	defer cleanup()
+
+	transport := NewTcpTransport()
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if transport.FinalInfo() != nil {
+		t.Error("Expected no final info before close")
+	}
+
+	if err := transport.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if transport.FinalInfo() == nil {
+		t.Error("Expected final info to be captured at close")
+	}
+}
+
+func TestTcpTransport_Info_NoConnection(t *testing.T) {
+	_, err := NewTcpTransport().Info()
+	expectTransportError(t, err, httperrors.SocketReadFailure)
+}
@@ -0,0 +1,14 @@
+//go:build !linux || 386
+
+package transport
+
+import (
+	"errors"
+	"net"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func tcpInfo(conn net.Conn) (*TcpInfo, error) {
+	return nil, httperrors.NewTransportError(httperrors.SocketReadFailure, errors.ErrUnsupported)
+}
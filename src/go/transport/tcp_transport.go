@@ -1,11 +1,12 @@
 package transport
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"io"
 	"net"
-	"syscall"
+	"strconv"
+	"time"
 
 	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
 )
@@ -13,37 +14,185 @@ import (
 // TcpTransport implements the Transport interface using TCP sockets
 type TcpTransport struct {
 	conn net.Conn
+	// Options holds the connect, read and write timeouts.
+	Options TransportOptions
+	// Health tracks recently-failed addresses across connects.
+	// It defaults to a process-wide instance.
+	Health *AddrHealth
+	// AbortiveClose makes Close behave like Abort, for load generators that
+	// would otherwise exhaust local ports with sockets in TIME_WAIT.
+	AbortiveClose bool
+	// LocalPortRange restricts the ephemeral ports used for outgoing
+	// connections via IP_LOCAL_PORT_RANGE (Linux 6.3+). The zero value keeps
+	// the system-wide range. On older kernels Connect fails with InitFailure
+	// wrapping errors.ErrUnsupported before dialing.
+	LocalPortRange [2]uint16
+	// ReuseAddr sets SO_REUSEADDR on outgoing sockets (Linux only).
+	ReuseAddr bool
+	// lookupIPAddr resolves host names; replaced in tests
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	// dialContext dials a single address; replaced in tests
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	lookupSRV   lookupSRVFunc
+	finalInfo   *TcpInfo
+}
+
+// connectionAttemptDelay is how long an attempt may stay pending before the
+// next address is dialed alongside it, as recommended by RFC 8305
+const connectionAttemptDelay = 250 * time.Millisecond
+
+// dialResult is the outcome of a single connection attempt
+type dialResult struct {
+	addr    string
+	conn    net.Conn
+	err     error
+	elapsed time.Duration
+}
+
+// partialDeadline returns the deadline for one attempt when addrsRemaining
+// addresses still have to share the time left until deadline. Like
+// net.Dialer, it gives each attempt at least two seconds where possible so
+// that a slow first address does not starve the ones after it.
+func partialDeadline(now, deadline time.Time, addrsRemaining int) time.Time {
+	timeRemaining := deadline.Sub(now)
+	timeout := timeRemaining / time.Duration(addrsRemaining)
+	const saneMinimum = 2 * time.Second
+	if timeout < saneMinimum {
+		timeout = min(timeRemaining, saneMinimum)
+	}
+	return now.Add(timeout)
 }
 
 // NewTcpTransport creates a new TcpTransport instance
 func NewTcpTransport() *TcpTransport {
 	return &TcpTransport{
-		conn: nil,
+		conn:         nil,
+		Health:       defaultAddrHealth,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		lookupSRV:    net.DefaultResolver.LookupSRV,
 	}
 }
 
-// Connect establishes a TCP connection to the specified host and port
+// Connect establishes a TCP connection to the specified host and port.
+// When the host resolves to several addresses, each is tried in turn until
+// one connects, starting with those that have not failed recently. An
+// attempt that is still pending after 250ms races the next address rather
+// than holding it up, and with a deadline each attempt only gets its share
+// of the remaining time. If all of them fail, the SocketConnectFailure wraps a *DialError listing each attempt.
+// If any attempt failed because no local port was free, LocalAddrUnavailable
+// is returned instead so load generators can back off rather than retry.
 func (t *TcpTransport) Connect(host string, port uint16) error {
-	addr := fmt.Sprintf("%s:%d", host, port)
+	return t.ConnectCtx(context.Background(), host, port)
+}
+
+// ConnectCtx is Connect with cancellation of both name resolution and dialing
+func (t *TcpTransport) ConnectCtx(ctx context.Context, host string, port uint16) error {
+	ctx, cancel := t.Options.connectContext(ctx)
+	defer cancel()
 
-	conn, err := net.Dial("tcp", addr)
+	dialer, err := newDialer(t.LocalPortRange, t.ReuseAddr)
 	if err != nil {
-		// Classify network errors using type assertions
-		if opErr, ok := err.(*net.OpError); ok {
-			if opErr.Op == "dial" {
-				// Check for DNS resolution failures
-				if dnsErr, ok := opErr.Err.(*net.DNSError); ok {
-					if dnsErr.IsNotFound || dnsErr.IsTemporary {
-						return httperrors.NewTransportError(httperrors.DnsFailure, err)
-					}
+		return httperrors.NewTransportError(httperrors.InitFailure, err)
+	}
+
+	lookup := t.lookupIPAddr
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIPAddr
+	}
+	resolveStart := time.Now()
+	addrs, err := lookup(ctx, host)
+	dialErr := &DialError{Host: host, Port: port, Resolve: time.Since(resolveStart)}
+	if ctx.Err() != nil {
+		return contextError(ctx)
+	}
+	if err != nil {
+		return httperrors.NewTransportError(httperrors.DnsFailure, err)
+	}
+
+	health := t.Health
+	if health == nil {
+		health = defaultAddrHealth
+	}
+	dial := t.dialContext
+	if dial == nil {
+		dial = dialer.DialContext
+	}
+
+	ordered := health.Order(addrs, port)
+	dialCtx, cancelDials := context.WithCancel(ctx)
+	results := make(chan dialResult, len(ordered))
+	next, pending := 0, 0
+	defer func() {
+		cancelDials()
+		// Close connections from attempts that lost the race
+		go func(pending int) {
+			for ; pending > 0; pending-- {
+				if r := <-results; r.conn != nil {
+					r.conn.Close()
 				}
-				// Check for connection refused via syscall error
-				if errors.Is(opErr.Err, syscall.ECONNREFUSED) {
-					return httperrors.NewTransportError(httperrors.SocketConnectFailure, err)
+			}
+		}(pending)
+	}()
+
+	localExhausted := false
+	var conn net.Conn
+	for conn == nil {
+		if next < len(ordered) {
+			addr := net.JoinHostPort(ordered[next].String(), strconv.Itoa(int(port)))
+			attemptCtx, cancelAttempt := dialCtx, context.CancelFunc(func() {})
+			if deadline, ok := ctx.Deadline(); ok {
+				attemptCtx, cancelAttempt = context.WithDeadline(dialCtx,
+					partialDeadline(time.Now(), deadline, len(ordered)-next))
+			}
+			go func() {
+				defer cancelAttempt()
+				start := time.Now()
+				c, err := dial(attemptCtx, "tcp", addr)
+				results <- dialResult{addr: addr, conn: c, err: err, elapsed: time.Since(start)}
+			}()
+			next++
+			pending++
+		}
+		if pending == 0 {
+			break
+		}
+
+		// Give the pending attempts a head start before racing the next address
+		var timer *time.Timer
+		var delay <-chan time.Time
+		if next < len(ordered) {
+			timer = time.NewTimer(connectionAttemptDelay)
+			delay = timer.C
+		}
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				health.MarkHealthy(r.addr)
+				conn = r.conn
+			} else if ctx.Err() != nil {
+				return contextError(ctx)
+			} else {
+				// Running out of local ports says nothing about the remote address
+				if isAddrNotAvailable(r.err) {
+					localExhausted = true
+				} else {
+					health.MarkFailed(r.addr)
 				}
+				dialErr.Attempts = append(dialErr.Attempts, newDialAttempt(r.addr, r.err, r.elapsed))
 			}
+		case <-delay:
+		}
+		if timer != nil {
+			timer.Stop()
 		}
-		return httperrors.NewTransportError(httperrors.SocketConnectFailure, err)
+	}
+
+	if conn == nil {
+		if localExhausted {
+			return httperrors.NewTransportError(httperrors.LocalAddrUnavailable, dialErr)
+		}
+		return httperrors.NewTransportError(httperrors.SocketConnectFailure, dialErr)
 	}
 
 	// Set TCP_NODELAY to disable Nagle's algorithm for lower latency
@@ -55,19 +204,31 @@ func (t *TcpTransport) Connect(host string, port uint16) error {
 	}
 
 	t.conn = conn
+	t.finalInfo = nil
 	return nil
 }
 
 // Write sends data over the TCP connection
 func (t *TcpTransport) Write(buf []byte) (int, error) {
+	return t.write(context.Background(), buf)
+}
+
+// write is Write bounded by both ctx's deadline and WriteTimeout
+func (t *TcpTransport) write(ctx context.Context, buf []byte) (int, error) {
 	if t.conn == nil {
 		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
 	}
 
+	if err := t.Options.armWrite(ctx, t.conn); err != nil {
+		return 0, err
+	}
 	n, err := t.conn.Write(buf)
 	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
 		// Check for broken pipe or connection reset
-		if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		if isConnectionClosed(err) {
 			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
 		}
 		return n, httperrors.NewTransportError(httperrors.SocketWriteFailure, err)
@@ -76,14 +237,33 @@ func (t *TcpTransport) Write(buf []byte) (int, error) {
 	return n, nil
 }
 
+// WriteCtx is Write with cancellation
+func (t *TcpTransport) WriteCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Write(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.write(ctx, buf) })
+}
+
 // Read receives data from the TCP connection
 func (t *TcpTransport) Read(buf []byte) (int, error) {
+	return t.read(context.Background(), buf)
+}
+
+// read is Read bounded by both ctx's deadline and ReadTimeout
+func (t *TcpTransport) read(ctx context.Context, buf []byte) (int, error) {
 	if t.conn == nil {
 		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
 	}
 
+	if err := t.Options.armRead(ctx, t.conn); err != nil {
+		return 0, err
+	}
 	n, err := t.conn.Read(buf)
 	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
 		if errors.Is(err, io.EOF) || (n == 0 && len(buf) > 0) {
 			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
 		}
@@ -93,11 +273,55 @@ func (t *TcpTransport) Read(buf []byte) (int, error) {
 	return n, nil
 }
 
+// ReadCtx is Read with cancellation
+func (t *TcpTransport) ReadCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Read(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.read(ctx, buf) })
+}
+
+// Probe checks whether an idle TCP connection is still usable without blocking
+func (t *TcpTransport) Probe() error {
+	if t.conn == nil {
+		return httperrors.NewTransportError(httperrors.ConnectionClosed, nil)
+	}
+	return probeConn(t.conn)
+}
+
 // Close closes the TCP connection
 func (t *TcpTransport) Close() error {
 	if t.conn == nil {
 		return nil // Idempotent close
 	}
+	if t.AbortiveClose {
+		return t.Abort()
+	}
+	return t.close()
+}
+
+// Abort closes the TCP connection with SO_LINGER set to zero, so the kernel
+// sends RST and discards unsent data instead of shutting down gracefully
+func (t *TcpTransport) Abort() error {
+	if t.conn == nil {
+		return nil
+	}
+	if tc, ok := t.conn.(*net.TCPConn); ok {
+		if err := tc.SetLinger(0); err != nil {
+			t.conn.Close()
+			t.conn = nil
+			return httperrors.NewTransportError(httperrors.SocketCloseFailure, err)
+		}
+	}
+	return t.close()
+}
+
+// close snapshots the kernel statistics and releases the connection
+func (t *TcpTransport) close() error {
+	// Snapshot kernel statistics while the socket still exists
+	if info, err := tcpInfo(t.conn); err == nil {
+		t.finalInfo = info
+	}
 
 	err := t.conn.Close()
 	t.conn = nil
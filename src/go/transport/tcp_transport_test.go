@@ -2,7 +2,6 @@ package transport
 
 import (
 	"net"
-	"syscall"
 	"testing"
 	"time"
 
@@ -263,8 +262,7 @@ func TestTcpTransport_Write_Failure_ClosedConnection(t *testing.T) {
 			raw, err := tcpConn.SyscallConn()
 			if err == nil {
 				raw.Control(func(fd uintptr) {
-					linger := syscall.Linger{Onoff: 1, Linger: 0}
-					syscall.SetsockoptLinger(int(fd), syscall.SOL_SOCKET, syscall.SO_LINGER, &linger)
+					setLingerZero(fd)
 				})
 			}
 		}
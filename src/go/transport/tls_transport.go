@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// closeNotifyTimeout bounds how long Close waits to send close_notify
+const closeNotifyTimeout = time.Second
+
+// TlsTransport implements the Transport interface using TLS over a TcpTransport
+type TlsTransport struct {
+	// Tcp is the underlying transport. Its options (Options, Health,
+	// AbortiveClose, ...) apply to the TLS connection as well; the
+	// ConnectTimeout covers the handshake too.
+	Tcp *TcpTransport
+	// Config is the TLS client configuration. ServerName defaults to the
+	// host passed to Connect, also with InsecureSkipVerify.
+	Config *tls.Config
+	conn   *tls.Conn
+}
+
+// NewTlsTransport creates a new TlsTransport instance. A nil config uses
+// the system roots and the default TLS settings.
+func NewTlsTransport(config *tls.Config) *TlsTransport {
+	return &TlsTransport{
+		Tcp:    NewTcpTransport(),
+		Config: config,
+	}
+}
+
+// Connect establishes a TCP connection and performs the TLS handshake.
+// Certificate verification failures are reported as TlsCertificateFailure,
+// any other handshake failure as TlsHandshakeFailure.
+func (t *TlsTransport) Connect(host string, port uint16) error {
+	return t.ConnectCtx(context.Background(), host, port)
+}
+
+// ConnectCtx is Connect with cancellation of the TCP connect and the handshake
+func (t *TlsTransport) ConnectCtx(ctx context.Context, host string, port uint16) error {
+	ctx, cancel := t.Tcp.Options.connectContext(ctx)
+	defer cancel()
+
+	if err := t.Tcp.ConnectCtx(ctx, host, port); err != nil {
+		return err
+	}
+
+	var config *tls.Config
+	if t.Config != nil {
+		config = t.Config.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	// Default ServerName even when verification is skipped, so that SNI is
+	// still sent to virtual hosts
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+
+	conn := tls.Client(t.Tcp.conn, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		t.Tcp.Close()
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
+		return classifyHandshakeError(err)
+	}
+
+	t.conn = conn
+	return nil
+}
+
+// classifyHandshakeError separates certificate problems from other handshake failures
+func classifyHandshakeError(err error) error {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &verifyErr) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return httperrors.NewTransportError(httperrors.TlsCertificateFailure, err)
+	}
+	if isConnectionClosed(err) || errors.Is(err, io.EOF) {
+		return httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+	}
+	return httperrors.NewTransportError(httperrors.TlsHandshakeFailure, err)
+}
+
+// ConnectionState returns the negotiated TLS parameters, or false if not connected
+func (t *TlsTransport) ConnectionState() (tls.ConnectionState, bool) {
+	if t.conn == nil {
+		return tls.ConnectionState{}, false
+	}
+	return t.conn.ConnectionState(), true
+}
+
+// Write sends data over the TLS connection
+func (t *TlsTransport) Write(buf []byte) (int, error) {
+	return t.write(context.Background(), buf)
+}
+
+// write is Write bounded by both ctx's deadline and WriteTimeout
+func (t *TlsTransport) write(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
+	}
+
+	if err := t.Tcp.Options.armWrite(ctx, t.conn); err != nil {
+		return 0, err
+	}
+	n, err := t.conn.Write(buf)
+	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
+		if isConnectionClosed(err) {
+			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+		}
+		return n, httperrors.NewTransportError(httperrors.SocketWriteFailure, err)
+	}
+
+	return n, nil
+}
+
+// WriteCtx is Write with cancellation. A cancelled write leaves the TLS
+// connection unusable, as crypto/tls does not recover from write timeouts.
+func (t *TlsTransport) WriteCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Write(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.write(ctx, buf) })
+}
+
+// Read receives data from the TLS connection
+func (t *TlsTransport) Read(buf []byte) (int, error) {
+	return t.read(context.Background(), buf)
+}
+
+// read is Read bounded by both ctx's deadline and ReadTimeout
+func (t *TlsTransport) read(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
+	}
+
+	if err := t.Tcp.Options.armRead(ctx, t.conn); err != nil {
+		return 0, err
+	}
+	n, err := t.conn.Read(buf)
+	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
+		if errors.Is(err, io.EOF) || isConnectionClosed(err) || (n == 0 && len(buf) > 0) {
+			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
+		}
+		return n, httperrors.NewTransportError(httperrors.SocketReadFailure, err)
+	}
+
+	return n, nil
+}
+
+// ReadCtx is Read with cancellation
+func (t *TlsTransport) ReadCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Read(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.read(ctx, buf) })
+}
+
+// Close sends a close_notify alert and closes the TCP connection
+func (t *TlsTransport) Close() error {
+	if t.conn == nil {
+		return nil // Idempotent close
+	}
+
+	// close_notify is best effort; the peer may already be gone or not be
+	// reading. crypto/tls arms its own longer write deadline for the alert,
+	// so the timer expires the deadline once closeNotifyTimeout has passed.
+	if !t.Tcp.AbortiveClose {
+		raw := t.Tcp.conn
+		expire := time.AfterFunc(closeNotifyTimeout, func() { raw.SetWriteDeadline(time.Unix(1, 0)) })
+		t.conn.CloseWrite()
+		expire.Stop()
+	}
+	t.conn = nil
+	return t.Tcp.Close()
+}
+
+// Abort closes the TCP connection abortively without a close_notify alert
+func (t *TlsTransport) Abort() error {
+	if t.conn == nil {
+		return nil
+	}
+	t.conn = nil
+	return t.Tcp.Abort()
+}
@@ -0,0 +1,200 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// newTestCertificate creates a self-signed certificate for localhost and
+// 127.0.0.1 along with a pool that trusts it
+func newTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "httpgo test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+// setupTlsTestServer runs serverLogic on the first accepted connection after
+// completing the TLS handshake
+func setupTlsTestServer(t *testing.T, cert tls.Certificate, serverLogic func(net.Conn)) (string, uint16, func()) {
+	t.Helper()
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	return setupTcpTestServer(t, func(conn net.Conn) {
+		tlsConn := tls.Server(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		serverLogic(tlsConn)
+		tlsConn.Close()
+	})
+}
+
+func TestTlsTransport_Connect_Success(t *testing.T) {
+	cert, pool := newTestCertificate(t)
+	host, port, cleanup := setupTlsTestServer(t, cert, func(conn net.Conn) {
+		buf := make([]byte, 4)
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+		conn.Write([]byte("pong"))
+	})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{RootCAs: pool})
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	state, ok := transport.ConnectionState()
+	if !ok || !state.HandshakeComplete {
+		t.Fatal("Expected a completed handshake")
+	}
+	if state.Version != tls.VersionTLS13 {
+		t.Errorf("Expected TLS 1.3, got %x", state.Version)
+	}
+
+	if _, err := transport.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	n, err := transport.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(buf[:n]) != "pong" {
+		t.Errorf("Expected pong, got %q", buf[:n])
+	}
+}
+
+func TestTlsTransport_Connect_SendsSniWithInsecureSkipVerify(t *testing.T) {
+	cert, _ := newTestCertificate(t)
+	serverNames := make(chan string, 1)
+	config := &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverNames <- hello.ServerName
+			return nil, nil
+		},
+		Certificates: []tls.Certificate{cert},
+	}
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		tls.Server(conn, config).Handshake()
+	})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{InsecureSkipVerify: true})
+	transport.Tcp.lookupIPAddr = func(ctx context.Context, name string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP(host)}}, nil
+	}
+	if err := transport.Connect("vhost.test", port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	if got := <-serverNames; got != "vhost.test" {
+		t.Errorf("Expected SNI vhost.test, got %q", got)
+	}
+}
+
+func TestTlsTransport_Read_PeerClosed(t *testing.T) {
+	cert, pool := newTestCertificate(t)
+	host, port, cleanup := setupTlsTestServer(t, cert, func(conn net.Conn) {})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{RootCAs: pool})
+	if err := transport.Connect(host, port); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer transport.Close()
+
+	_, err := transport.Read(make([]byte, 16))
+	expectTransportError(t, err, httperrors.ConnectionClosed)
+}
+
+func TestTlsTransport_Connect_UnknownAuthority(t *testing.T) {
+	cert, _ := newTestCertificate(t)
+	host, port, cleanup := setupTlsTestServer(t, cert, func(conn net.Conn) {})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{RootCAs: x509.NewCertPool()})
+	err := transport.Connect(host, port)
+	expectTransportError(t, err, httperrors.TlsCertificateFailure)
+}
+
+func TestTlsTransport_Connect_HostnameMismatch(t *testing.T) {
+	cert, pool := newTestCertificate(t)
+	host, port, cleanup := setupTlsTestServer(t, cert, func(conn net.Conn) {})
+	defer cleanup()
+
+	transport := NewTlsTransport(&tls.Config{RootCAs: pool, ServerName: "example.com"})
+	err := transport.Connect(host, port)
+	expectTransportError(t, err, httperrors.TlsCertificateFailure)
+}
+
+func TestTlsTransport_Connect_PlaintextServer(t *testing.T) {
+	host, port, cleanup := setupTcpTestServer(t, func(conn net.Conn) {
+		buf := make([]byte, 1024)
+		conn.Read(buf)
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\n\r\n"))
+	})
+	defer cleanup()
+
+	transport := NewTlsTransport(nil)
+	err := transport.Connect(host, port)
+	expectTransportError(t, err, httperrors.TlsHandshakeFailure)
+}
+
+func TestTlsTransport_Connect_TcpFailure(t *testing.T) {
+	transport := NewTlsTransport(nil)
+	err := transport.Connect("127.0.0.1", 1)
+	expectTransportError(t, err, httperrors.SocketConnectFailure)
+}
+
+func TestTlsTransport_NotConnected(t *testing.T) {
+	transport := NewTlsTransport(nil)
+
+	_, err := transport.Write([]byte("x"))
+	expectTransportError(t, err, httperrors.SocketWriteFailure)
+	_, err = transport.Read(make([]byte, 1))
+	expectTransportError(t, err, httperrors.SocketReadFailure)
+	if err := transport.Close(); err != nil {
+		t.Errorf("Expected Close without Connect to succeed, got %v", err)
+	}
+}
@@ -1,7 +1,7 @@
 package transport
 
 // Transport defines the interface for network I/O operations.
-// Implementations include TCP and Unix domain sockets.
+// Implementations include TCP, TLS and Unix domain sockets.
 type Transport interface {
 	// Connect establishes a connection to the specified host and port.
 	// For Unix sockets, the host parameter is the socket path and port is ignored.
@@ -18,3 +18,20 @@ type Transport interface {
 	// Close closes the connection.
 	Close() error
 }
+
+// Prober is implemented by transports that can cheaply check whether an
+// idle connection is still usable before it is reused for another request.
+type Prober interface {
+	// Probe returns nil if the connection looks usable. It never blocks and
+	// never consumes data. A connection the peer has closed or reset yields
+	// a ConnectionClosed error.
+	Probe() error
+}
+
+// Aborter is implemented by transports that can tear a connection down
+// abortively, sending RST instead of FIN so the socket skips TIME_WAIT.
+type Aborter interface {
+	// Abort closes the connection without a graceful shutdown. Unsent data
+	// is discarded. Like Close, it is idempotent.
+	Abort() error
+}
@@ -1,11 +1,11 @@
 package transport
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net"
 	"os"
-	"syscall"
 
 	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
 )
@@ -13,6 +13,8 @@ import (
 // UnixTransport implements the Transport interface using Unix domain sockets
 type UnixTransport struct {
 	conn net.Conn
+	// Options holds the connect, read and write timeouts.
+	Options TransportOptions
 }
 
 // NewUnixTransport creates a new UnixTransport instance
@@ -25,13 +27,25 @@ func NewUnixTransport() *UnixTransport {
 // Connect establishes a Unix domain socket connection to the specified path.
 // The port parameter is ignored for Unix sockets.
 func (t *UnixTransport) Connect(path string, port uint16) error {
-	conn, err := net.Dial("unix", path)
+	return t.ConnectCtx(context.Background(), path, port)
+}
+
+// ConnectCtx is Connect with cancellation
+func (t *UnixTransport) ConnectCtx(ctx context.Context, path string, port uint16) error {
+	ctx, cancel := t.Options.connectContext(ctx)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", path)
 	if err != nil {
+		if ctx.Err() != nil {
+			return contextError(ctx)
+		}
 		// Classify errors using type assertions and os package helpers
-		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOENT) {
+		if errors.Is(err, os.ErrNotExist) {
 			return httperrors.NewTransportError(httperrors.SocketConnectFailure, err)
 		}
-		if errors.Is(err, syscall.ECONNREFUSED) {
+		if isConnectionRefused(err) {
 			return httperrors.NewTransportError(httperrors.SocketConnectFailure, err)
 		}
 		return httperrors.NewTransportError(httperrors.SocketConnectFailure, err)
@@ -43,14 +57,25 @@ func (t *UnixTransport) Connect(path string, port uint16) error {
 
 // Write sends data over the Unix domain socket
 func (t *UnixTransport) Write(buf []byte) (int, error) {
+	return t.write(context.Background(), buf)
+}
+
+// write is Write bounded by both ctx's deadline and WriteTimeout
+func (t *UnixTransport) write(ctx context.Context, buf []byte) (int, error) {
 	if t.conn == nil {
 		return 0, httperrors.NewTransportError(httperrors.SocketWriteFailure, nil)
 	}
 
+	if err := t.Options.armWrite(ctx, t.conn); err != nil {
+		return 0, err
+	}
 	n, err := t.conn.Write(buf)
 	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
 		// Check for broken pipe or connection reset
-		if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		if isConnectionClosed(err) {
 			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
 		}
 		return n, httperrors.NewTransportError(httperrors.SocketWriteFailure, err)
@@ -59,14 +84,33 @@ func (t *UnixTransport) Write(buf []byte) (int, error) {
 	return n, nil
 }
 
+// WriteCtx is Write with cancellation
+func (t *UnixTransport) WriteCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Write(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.write(ctx, buf) })
+}
+
 // Read receives data from the Unix domain socket
 func (t *UnixTransport) Read(buf []byte) (int, error) {
+	return t.read(context.Background(), buf)
+}
+
+// read is Read bounded by both ctx's deadline and ReadTimeout
+func (t *UnixTransport) read(ctx context.Context, buf []byte) (int, error) {
 	if t.conn == nil {
 		return 0, httperrors.NewTransportError(httperrors.SocketReadFailure, nil)
 	}
 
+	if err := t.Options.armRead(ctx, t.conn); err != nil {
+		return 0, err
+	}
 	n, err := t.conn.Read(buf)
 	if err != nil {
+		if isTimeout(err) {
+			return n, httperrors.NewTransportError(httperrors.Timeout, err)
+		}
 		if errors.Is(err, io.EOF) || (n == 0 && len(buf) > 0) {
 			return n, httperrors.NewTransportError(httperrors.ConnectionClosed, err)
 		}
@@ -76,6 +120,22 @@ func (t *UnixTransport) Read(buf []byte) (int, error) {
 	return n, nil
 }
 
+// ReadCtx is Read with cancellation
+func (t *UnixTransport) ReadCtx(ctx context.Context, buf []byte) (int, error) {
+	if t.conn == nil {
+		return t.Read(buf)
+	}
+	return withContext(ctx, t.conn, func() (int, error) { return t.read(ctx, buf) })
+}
+
+// Probe checks whether an idle Unix domain socket connection is still usable without blocking
+func (t *UnixTransport) Probe() error {
+	if t.conn == nil {
+		return httperrors.NewTransportError(httperrors.ConnectionClosed, nil)
+	}
+	return probeConn(t.conn)
+}
+
 // Close closes the Unix domain socket connection
 func (t *UnixTransport) Close() error {
 	if t.conn == nil {
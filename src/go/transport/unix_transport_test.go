@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"sync/atomic"
-	"syscall"
 	"testing"
 	"time"
 
@@ -19,7 +19,7 @@ func setupUnixTestServer(t *testing.T, serverLogic func(net.Conn)) (string, func
 
 	// Generate unique socket path
 	count := atomic.AddUint64(&unixTestCounter, 1)
-	socketPath := fmt.Sprintf("/tmp/httpgo_test_%d_%d.sock", os.Getpid(), count)
+	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("httpgo_test_%d_%d.sock", os.Getpid(), count))
 
 	// Remove socket file if it exists
 	os.Remove(socketPath)
@@ -83,7 +83,7 @@ func TestUnixTransport_Connect_Success(t *testing.T) {
 
 func TestUnixTransport_Connect_Failure_NoSuchFile(t *testing.T) {
 	transport := NewUnixTransport()
-	err := transport.Connect("/tmp/this-socket-does-not-exist.sock", 0)
+	err := transport.Connect(filepath.Join(os.TempDir(), "this-socket-does-not-exist.sock"), 0)
 
 	if err == nil {
 		t.Fatal("Expected error on non-existent socket")
@@ -251,8 +251,7 @@ func TestUnixTransport_Write_Failure_ClosedConnection(t *testing.T) {
 			raw, err := unixConn.SyscallConn()
 			if err == nil {
 				raw.Control(func(fd uintptr) {
-					linger := syscall.Linger{Onoff: 1, Linger: 0}
-					syscall.SetsockoptLinger(int(fd), syscall.SOL_SOCKET, syscall.SO_LINGER, &linger)
+					setLingerZero(fd)
 				})
 			}
 		}
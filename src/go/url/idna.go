@@ -0,0 +1,149 @@
+package url
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxLabelLength = 63
+	maxHostLength  = 253
+)
+
+// ToASCII converts a host name to its ASCII form for use on the wire. Labels
+// containing non-ASCII characters are lowercased and punycode-encoded with
+// the "xn--" prefix (RFC 5891 / RFC 3492); ASCII labels are only lowercased.
+// The full UTS #46 mapping table is not applied.
+func ToASCII(host string) (string, error) {
+	// Ideographic and fullwidth full stops also separate labels
+	host = strings.NewReplacer("。", ".", "．", ".", "｡", ".").Replace(host)
+
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		label = strings.ToLower(label)
+		if !isASCII(label) {
+			if !utf8.ValidString(label) {
+				return "", fmt.Errorf("url: invalid UTF-8 in host %q", host)
+			}
+			encoded, err := punycodeEncode(label)
+			if err != nil {
+				return "", err
+			}
+			label = "xn--" + encoded
+		}
+		if len(label) > maxLabelLength {
+			return "", fmt.Errorf("url: host label %q longer than %d bytes", label, maxLabelLength)
+		}
+		labels[i] = label
+	}
+
+	ascii := strings.Join(labels, ".")
+	if len(ascii) > maxHostLength {
+		return "", fmt.Errorf("url: host longer than %d bytes", maxHostLength)
+	}
+	return ascii, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Bootstring parameters for punycode, RFC 3492 section 5
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycodeEncode implements the encoding procedure of RFC 3492 section 6.3
+func punycodeEncode(s string) (string, error) {
+	runes := []rune(s)
+	out := make([]byte, 0, len(s))
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	b := len(out)
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for h < len(runes) {
+		m := int(utf8.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		if (m - n) > (1<<31-1-delta)/(h+1) {
+			return "", fmt.Errorf("url: punycode overflow encoding %q", s)
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTMin {
+					t = punyTMin
+				} else if t > punyTMax {
+					t = punyTMax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out), nil
+}
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
@@ -0,0 +1,50 @@
+package url
+
+import "testing"
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"example.com", "example.com"},
+		{"EXAMPLE.com", "example.com"},
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"MÜNCHEN.de", "xn--mnchen-3ya.de"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"例え。テスト", "xn--r8jz45g.xn--zckzah"},
+	}
+	for _, tt := range tests {
+		got, err := ToASCII(tt.in)
+		if err != nil {
+			t.Errorf("ToASCII(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ToASCII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPunycodeEncode_RFC3492Samples(t *testing.T) {
+	// Samples from RFC 3492 section 7.1, lowercased
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"pročprostěnemluvíčesky", "proprostnemluvesky-uyb24dma41a"},
+		{"3年b組金八先生", "3b-ww4c5e180e575a65lsy2b"},
+	}
+	for _, tt := range tests {
+		got, err := punycodeEncode(tt.in)
+		if err != nil {
+			t.Errorf("punycodeEncode(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("punycodeEncode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
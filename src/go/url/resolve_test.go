@@ -0,0 +1,91 @@
+package url
+
+import "testing"
+
+func TestURL_Resolve_RFC3986Examples(t *testing.T) {
+	base, err := Parse("http://a/b/c/d;p?q")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// RFC 3986 sections 5.4.1 and 5.4.2, minus "g:h" which has no host
+	tests := map[string]string{
+		"g":             "http://a/b/c/g",
+		"./g":           "http://a/b/c/g",
+		"g/":            "http://a/b/c/g/",
+		"/g":            "http://a/g",
+		"//g":           "http://g",
+		"?y":            "http://a/b/c/d;p?y",
+		"g?y":           "http://a/b/c/g?y",
+		"#s":            "http://a/b/c/d;p?q#s",
+		"g#s":           "http://a/b/c/g#s",
+		"g?y#s":         "http://a/b/c/g?y#s",
+		";x":            "http://a/b/c/;x",
+		"g;x":           "http://a/b/c/g;x",
+		"":              "http://a/b/c/d;p?q",
+		".":             "http://a/b/c/",
+		"./":            "http://a/b/c/",
+		"..":            "http://a/b/",
+		"../":           "http://a/b/",
+		"../g":          "http://a/b/g",
+		"../..":         "http://a/",
+		"../../g":       "http://a/g",
+		"../../../g":    "http://a/g",
+		"../../../../g": "http://a/g",
+		"/./g":          "http://a/g",
+		"/../g":         "http://a/g",
+		"g.":            "http://a/b/c/g.",
+		".g":            "http://a/b/c/.g",
+		"g..":           "http://a/b/c/g..",
+		"..g":           "http://a/b/c/..g",
+		"./../g":        "http://a/b/g",
+		"./g/.":         "http://a/b/c/g/",
+		"g/./h":         "http://a/b/c/g/h",
+		"g/../h":        "http://a/b/c/h",
+		"g;x=1/./y":     "http://a/b/c/g;x=1/y",
+		"g;x=1/../y":    "http://a/b/c/y",
+	}
+	for ref, want := range tests {
+		got, err := base.Resolve(ref)
+		if err != nil {
+			t.Errorf("Resolve(%q) failed: %v", ref, err)
+			continue
+		}
+		if got.String() != want {
+			t.Errorf("Resolve(%q) = %q, want %q", ref, got.String(), want)
+		}
+	}
+}
+
+func TestURL_Resolve_Redirect(t *testing.T) {
+	base, err := Parse("https://api.example.com:8443/v1/items?page=1")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	next, err := base.Resolve("/v1/items?page=2")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if next.String() != "https://api.example.com:8443/v1/items?page=2" {
+		t.Errorf("Unexpected resolved URL %q", next.String())
+	}
+
+	other, err := base.Resolve("//bücher.example/x")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if other.String() != "https://xn--bcher-kva.example/x" {
+		t.Errorf("Expected scheme-relative reference with IDN host, got %q", other.String())
+	}
+}
+
+func TestURL_Resolve_RejectsNonHierarchicalTarget(t *testing.T) {
+	base, err := Parse("http://a/b")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := base.Resolve("mailto:someone@example.com"); err == nil {
+		t.Error("Expected an error for a target without a host")
+	}
+}
@@ -0,0 +1,277 @@
+package url
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// templateOp describes how one RFC 6570 expression type joins and escapes
+// its values (RFC 6570 appendix A)
+type templateOp struct {
+	first         string
+	sep           string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+var templateOps = map[byte]templateOp{
+	0:   {first: "", sep: ","},
+	'+': {first: "", sep: ",", allowReserved: true},
+	'#': {first: "#", sep: ",", allowReserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+// Expand expands a URI template (RFC 6570, up to level 4) such as
+// "/users/{id}/repos{?page,per_page}", escaping each value as its expression
+// type requires. Variable values may be strings, other scalars (formatted
+// with %v), []string lists, map[string]string associative arrays (expanded
+// in key order) or [][2]string ordered pairs. Missing and nil variables and
+// empty lists are undefined and expand to nothing.
+func Expand(template string, vars map[string]any) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(template); {
+		c := template[i]
+		switch c {
+		case '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return "", templateError("unterminated expression at offset %d", i)
+			}
+			if err := expandExpression(&b, template[i+1:i+end], vars); err != nil {
+				return "", err
+			}
+			i += end + 1
+		case '}':
+			return "", templateError("unexpected '}' at offset %d", i)
+		default:
+			// Literals keep reserved characters and existing escapes
+			end := strings.IndexAny(template[i:], "{}")
+			if end < 0 {
+				end = len(template) - i
+			}
+			b.WriteString(escapeTemplateValue(template[i:i+end], true))
+			i += end
+		}
+	}
+	return b.String(), nil
+}
+
+func expandExpression(b *strings.Builder, expr string, vars map[string]any) error {
+	if expr == "" {
+		return templateError("empty expression")
+	}
+
+	var opChar byte
+	if strings.IndexByte("+#./;?&", expr[0]) >= 0 {
+		opChar = expr[0]
+		expr = expr[1:]
+	} else if strings.IndexByte("=,!@|", expr[0]) >= 0 {
+		return templateError("reserved operator %q", expr[0])
+	}
+	op := templateOps[opChar]
+
+	first := true
+	for _, spec := range strings.Split(expr, ",") {
+		name, prefix, explode, err := parseVarSpec(spec)
+		if err != nil {
+			return err
+		}
+
+		value, ok := templateValue(vars[name])
+		if !ok {
+			continue
+		}
+		if first {
+			b.WriteString(op.first)
+			first = false
+		} else {
+			b.WriteString(op.sep)
+		}
+
+		switch v := value.(type) {
+		case string:
+			if prefix > 0 {
+				v = truncateRunes(v, prefix)
+			}
+			writeNamed(b, op, name, v)
+		case []string:
+			if prefix > 0 {
+				return templateError("prefix modifier on list variable %q", name)
+			}
+			if explode {
+				for i, item := range v {
+					if i > 0 {
+						b.WriteString(op.sep)
+					}
+					writeNamed(b, op, name, item)
+				}
+				continue
+			}
+			if op.named {
+				b.WriteString(name + "=")
+			}
+			for i, item := range v {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(escapeTemplateValue(item, op.allowReserved))
+			}
+		case [][2]string:
+			if prefix > 0 {
+				return templateError("prefix modifier on associative variable %q", name)
+			}
+			if explode {
+				for i, kv := range v {
+					if i > 0 {
+						b.WriteString(op.sep)
+					}
+					if op.named {
+						writeNamed(b, op, escapeTemplateValue(kv[0], op.allowReserved), kv[1])
+					} else {
+						b.WriteString(escapeTemplateValue(kv[0], op.allowReserved) + "=" +
+							escapeTemplateValue(kv[1], op.allowReserved))
+					}
+				}
+				continue
+			}
+			if op.named {
+				b.WriteString(name + "=")
+			}
+			for i, kv := range v {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(escapeTemplateValue(kv[0], op.allowReserved) + "," +
+					escapeTemplateValue(kv[1], op.allowReserved))
+			}
+		}
+	}
+	return nil
+}
+
+// writeNamed writes one value, preceded by "name=" for the named operators
+func writeNamed(b *strings.Builder, op templateOp, name, value string) {
+	if op.named {
+		b.WriteString(name)
+		if value == "" {
+			b.WriteString(op.ifEmpty)
+			return
+		}
+		b.WriteByte('=')
+	}
+	b.WriteString(escapeTemplateValue(value, op.allowReserved))
+}
+
+// parseVarSpec parses "name", "name:prefix" or "name*"
+func parseVarSpec(spec string) (name string, prefix int, explode bool, err error) {
+	name = spec
+	if strings.HasSuffix(name, "*") {
+		name, explode = name[:len(name)-1], true
+	} else if i := strings.IndexByte(name, ':'); i >= 0 {
+		digits := name[i+1:]
+		name = name[:i]
+		if len(digits) == 0 || len(digits) > 4 || digits[0] == '0' {
+			return "", 0, false, templateError("invalid prefix in %q", spec)
+		}
+		for j := 0; j < len(digits); j++ {
+			if digits[j] < '0' || digits[j] > '9' {
+				return "", 0, false, templateError("invalid prefix in %q", spec)
+			}
+			prefix = prefix*10 + int(digits[j]-'0')
+		}
+	}
+
+	if name == "" {
+		return "", 0, false, templateError("empty variable name in %q", spec)
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return "", 0, false, templateError("invalid variable name %q", name)
+		}
+	}
+	return name, prefix, explode, nil
+}
+
+// templateValue normalizes a variable to string, []string or [][2]string.
+// It reports false for undefined values.
+func templateValue(v any) (any, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return v, true
+	case []string:
+		return v, len(v) > 0
+	case [][2]string:
+		return v, len(v) > 0
+	case map[string]string:
+		if len(v) == 0 {
+			return nil, false
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([][2]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = [2]string{k, v[k]}
+		}
+		return pairs, true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// escapeTemplateValue percent-encodes everything except unreserved
+// characters, and with allowReserved also keeps reserved characters and
+// existing percent-encoded triplets
+func escapeTemplateValue(s string, allowReserved bool) string {
+	const upperHex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isUnreserved(c):
+			b.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			b.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 2
+		default:
+			b.WriteByte('%')
+			b.WriteByte(upperHex[c>>4])
+			b.WriteByte(upperHex[c&0xF])
+		}
+	}
+	return b.String()
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+func templateError(format string, args ...any) error {
+	return httperrors.NewHttpError(httperrors.UrlParseFailure,
+		fmt.Errorf("url: template: "+format, args...))
+}
@@ -0,0 +1,139 @@
+package url
+
+import (
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// rfc6570Vars are the example variables from RFC 6570 section 3.2
+var rfc6570Vars = map[string]any{
+	"count":      []string{"one", "two", "three"},
+	"dom":        []string{"example", "com"},
+	"dub":        "me/too",
+	"hello":      "Hello World!",
+	"half":       "50%",
+	"var":        "value",
+	"who":        "fred",
+	"base":       "http://example.com/home/",
+	"path":       "/foo/bar",
+	"list":       []string{"red", "green", "blue"},
+	"keys":       [][2]string{{"semi", ";"}, {"dot", "."}, {"comma", ","}},
+	"v":          "6",
+	"x":          "1024",
+	"y":          "768",
+	"empty":      "",
+	"empty_keys": [][2]string{},
+	"undef":      nil,
+}
+
+func TestExpand_RFC6570Examples(t *testing.T) {
+	tests := map[string]string{
+		"{var}":            "value",
+		"{hello}":          "Hello%20World%21",
+		"{half}":           "50%25",
+		"O{empty}X":        "OX",
+		"O{undef}X":        "OX",
+		"{x,y}":            "1024,768",
+		"{x,hello,y}":      "1024,Hello%20World%21,768",
+		"?{x,empty}":       "?1024,",
+		"?{x,undef}":       "?1024",
+		"{var:3}":          "val",
+		"{var:30}":         "value",
+		"{list}":           "red,green,blue",
+		"{list*}":          "red,green,blue",
+		"{keys}":           "semi,%3B,dot,.,comma,%2C",
+		"{keys*}":          "semi=%3B,dot=.,comma=%2C",
+		"{+var}":           "value",
+		"{+hello}":         "Hello%20World!",
+		"{+half}":          "50%25",
+		"{+base}index":     "http://example.com/home/index",
+		"{+path}/here":     "/foo/bar/here",
+		"here?ref={+path}": "here?ref=/foo/bar",
+		"{+path:6}/here":   "/foo/b/here",
+		"{+list*}":         "red,green,blue",
+		"{+keys*}":         "semi=;,dot=.,comma=,",
+		"{#var}":           "#value",
+		"{#hello}":         "#Hello%20World!",
+		"{#path:6}/here":   "#/foo/b/here",
+		"{#keys}":          "#semi,;,dot,.,comma,,",
+		"X{.var}":          "X.value",
+		"X{.x,y}":          "X.1024.768",
+		"X{.list*}":        "X.red.green.blue",
+		"X{.keys*}":        "X.semi=%3B.dot=..comma=%2C",
+		"X{.empty_keys}":   "X",
+		"{/var}":           "/value",
+		"{/var,x}/here":    "/value/1024/here",
+		"{/list*,path:4}":  "/red/green/blue/%2Ffoo",
+		"{/keys*}":         "/semi=%3B/dot=./comma=%2C",
+		"{;x,y}":           ";x=1024;y=768",
+		"{;x,y,empty}":     ";x=1024;y=768;empty",
+		"{;list*}":         ";list=red;list=green;list=blue",
+		"{;keys*}":         ";semi=%3B;dot=.;comma=%2C",
+		"{?x,y}":           "?x=1024&y=768",
+		"{?x,y,empty}":     "?x=1024&y=768&empty=",
+		"{?list}":          "?list=red,green,blue",
+		"{?keys*}":         "?semi=%3B&dot=.&comma=%2C",
+		"?fixed=yes{&x}":   "?fixed=yes&x=1024",
+		"{&var:3}":         "&var=val",
+		"{&list*}":         "&list=red&list=green&list=blue",
+	}
+	for template, want := range tests {
+		got, err := Expand(template, rfc6570Vars)
+		if err != nil {
+			t.Errorf("Expand(%q) failed: %v", template, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Expand(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
+
+func TestExpand_EscapesPathInjection(t *testing.T) {
+	got, err := Expand("/users/{id}/repos{?page,per_page}", map[string]any{
+		"id":   "../admin?x=1",
+		"page": 2,
+	})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if got != "/users/..%2Fadmin%3Fx%3D1/repos?page=2" {
+		t.Errorf("Unexpected expansion %q", got)
+	}
+}
+
+func TestExpand_MapVariableUsesSortedKeys(t *testing.T) {
+	got, err := Expand("{?filter*}", map[string]any{
+		"filter": map[string]string{"state": "open", "label": "bug"},
+	})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if got != "?label=bug&state=open" {
+		t.Errorf("Unexpected expansion %q", got)
+	}
+}
+
+func TestExpand_Malformed(t *testing.T) {
+	for _, template := range []string{
+		"{var",
+		"var}",
+		"{}",
+		"{=var}",
+		"{var:0}",
+		"{var:10000}",
+		"{va r}",
+		"{list:2}",
+	} {
+		_, err := Expand(template, rfc6570Vars)
+		if err == nil {
+			t.Errorf("Expected error for %q", template)
+			continue
+		}
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.UrlParseFailure {
+			t.Errorf("Expected UrlParseFailure for %q, got %v", template, err)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package url provides the URL type shared by the redirect, caching, cookie
+// and client layers, so each of them works from one parsed and normalized
+// form instead of re-parsing strings.
+package url
+
+import (
+	"fmt"
+	"net"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+// URL is an absolute URL with an ASCII host and a validated port
+type URL struct {
+	u    neturl.URL
+	port uint16
+}
+
+// Parse parses an absolute URL. The host is lowercased and internationalized
+// domain names are converted to their ASCII (punycode) form.
+func Parse(raw string) (*URL, error) {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.UrlParseFailure, err)
+	}
+	return fromStd(u)
+}
+
+// fromStd validates a parsed net/url URL and converts its host
+func fromStd(u *neturl.URL) (*URL, error) {
+	if u.Scheme == "" {
+		return nil, httperrors.NewHttpError(httperrors.UrlParseFailure,
+			fmt.Errorf("url: missing scheme in %q", u.String()))
+	}
+	if u.Opaque != "" || u.Host == "" {
+		return nil, httperrors.NewHttpError(httperrors.UrlParseFailure,
+			fmt.Errorf("url: missing host in %q", u.String()))
+	}
+
+	host := u.Hostname()
+	if !strings.Contains(host, ":") {
+		ascii, err := ToASCII(host)
+		if err != nil {
+			return nil, httperrors.NewHttpError(httperrors.UrlParseFailure, err)
+		}
+		host = ascii
+	} else {
+		host = strings.ToLower(host)
+	}
+
+	var port uint16
+	if p := u.Port(); p != "" {
+		n, err := strconv.ParseUint(p, 10, 16)
+		if err != nil || n == 0 {
+			return nil, httperrors.NewHttpError(httperrors.UrlParseFailure,
+				fmt.Errorf("url: invalid port %q", p))
+		}
+		port = uint16(n)
+	}
+
+	out := &URL{u: *u, port: port}
+	out.setHost(host, port)
+	return out, nil
+}
+
+func (u *URL) setHost(host string, port uint16) {
+	u.port = port
+	if port == 0 {
+		if strings.Contains(host, ":") {
+			u.u.Host = "[" + host + "]"
+		} else {
+			u.u.Host = host
+		}
+		return
+	}
+	u.u.Host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// DefaultPort returns the well-known port for scheme, or 0 if there is none
+func DefaultPort(scheme string) uint16 {
+	switch strings.ToLower(scheme) {
+	case "http", "ws":
+		return 80
+	case "https", "wss":
+		return 443
+	}
+	return 0
+}
+
+// Scheme returns the lowercased scheme
+func (u *URL) Scheme() string {
+	return u.u.Scheme
+}
+
+// Hostname returns the ASCII host without brackets or port
+func (u *URL) Hostname() string {
+	return u.u.Hostname()
+}
+
+// Port returns the explicit port, or the scheme's default port if none was given
+func (u *URL) Port() uint16 {
+	if u.port != 0 {
+		return u.port
+	}
+	return DefaultPort(u.u.Scheme)
+}
+
+// HasExplicitPort reports whether the URL spelled out a port
+func (u *URL) HasExplicitPort() bool {
+	return u.port != 0
+}
+
+// HostHeader returns the value for the Host header (RFC 9110 section 7.2).
+// The port is omitted when it is the scheme's default, and IPv6 literals
+// are bracketed. An IPv6 zone is local to this machine and is left out.
+func (u *URL) HostHeader() string {
+	host := u.Hostname()
+	if strings.Contains(host, ":") {
+		host, _, _ = strings.Cut(host, "%")
+		host = "[" + host + "]"
+	}
+	if u.port == 0 || u.port == DefaultPort(u.u.Scheme) {
+		return host
+	}
+	return host + ":" + strconv.Itoa(int(u.port))
+}
+
+// Path returns the escaped path
+func (u *URL) Path() string {
+	return u.u.EscapedPath()
+}
+
+// RawQuery returns the encoded query without the leading '?'
+func (u *URL) RawQuery() string {
+	return u.u.RawQuery
+}
+
+// Query parses the query into its values
+func (u *URL) Query() neturl.Values {
+	return u.u.Query()
+}
+
+// Fragment returns the decoded fragment
+func (u *URL) Fragment() string {
+	return u.u.Fragment
+}
+
+// RequestURI returns the origin-form request target: the path and query
+func (u *URL) RequestURI() string {
+	return u.u.RequestURI()
+}
+
+// String reassembles the URL
+func (u *URL) String() string {
+	return u.u.String()
+}
+
+// Std returns a copy of the URL as a net/url URL
+func (u *URL) Std() *neturl.URL {
+	c := u.u
+	if c.User != nil {
+		user := *c.User
+		c.User = &user
+	}
+	return &c
+}
+
+// Normalize returns the syntax-based normal form from RFC 3986 section 6.2.2,
+// plus scheme-based port elision: the default port is dropped, percent-encoded
+// unreserved characters are decoded, other escapes use uppercase hex, dot
+// segments are removed and an empty path becomes "/".
+func (u *URL) Normalize() *URL {
+	out := &URL{u: *u.Std()}
+	port := u.port
+	if port == DefaultPort(u.u.Scheme) {
+		port = 0
+	}
+	out.setHost(u.Hostname(), port)
+
+	path := removeDotSegments(normalizePercent(u.u.EscapedPath()))
+	if path == "" {
+		path = "/"
+	}
+	out.setEscapedPath(path)
+	out.u.RawQuery = normalizePercent(u.u.RawQuery)
+	if u.u.RawFragment != "" {
+		out.u.RawFragment = normalizePercent(u.u.RawFragment)
+	}
+	return out
+}
+
+func (u *URL) setEscapedPath(p string) {
+	decoded, err := neturl.PathUnescape(p)
+	if err != nil {
+		// normalizePercent leaves malformed escapes alone; keep them raw
+		u.u.Path, u.u.RawPath = p, ""
+		return
+	}
+	u.u.Path, u.u.RawPath = decoded, p
+}
+
+// normalizePercent decodes percent-encoded unreserved characters and
+// uppercases the hex digits of every other escape
+func normalizePercent(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// removeDotSegments implements RFC 3986 section 5.2.4
+func removeDotSegments(path string) string {
+	var out []string
+	in := path
+	for in != "" {
+		switch {
+		case strings.HasPrefix(in, "../"):
+			in = in[3:]
+		case strings.HasPrefix(in, "./"):
+			in = in[2:]
+		case strings.HasPrefix(in, "/./"):
+			in = in[2:]
+		case in == "/.":
+			in = "/"
+		case strings.HasPrefix(in, "/../"):
+			in = in[3:]
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case in == "/..":
+			in = "/"
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case in == "." || in == "..":
+			in = ""
+		default:
+			start := 0
+			if in[0] == '/' {
+				start = 1
+			}
+			end := strings.IndexByte(in[start:], '/')
+			if end < 0 {
+				end = len(in)
+			} else {
+				end += start
+			}
+			out = append(out, in[:end])
+			in = in[end:]
+		}
+	}
+	return strings.Join(out, "")
+}
+
+func isUnreserved(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
+
+// Resolve resolves a URI reference, such as a Location or Link target,
+// against u as described in RFC 3986 section 5.2. An absolute reference is
+// returned as is; relative ones inherit u's scheme, authority and path.
+func (u *URL) Resolve(ref string) (*URL, error) {
+	r, err := neturl.Parse(ref)
+	if err != nil {
+		return nil, httperrors.NewHttpError(httperrors.UrlParseFailure, err)
+	}
+	return fromStd(u.Std().ResolveReference(r))
+}
@@ -0,0 +1,169 @@
+package url
+
+import (
+	"strings"
+	"testing"
+
+	httperrors "github.com/nczempin/0004_std_lib_http_client/httpgo/errors"
+)
+
+func TestParse_Accessors(t *testing.T) {
+	u, err := Parse("HTTP://Example.COM:8080/a%2Fb/c?x=1&y=two#frag")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if u.Scheme() != "http" {
+		t.Errorf("Expected scheme http, got %q", u.Scheme())
+	}
+	if u.Hostname() != "example.com" {
+		t.Errorf("Expected host example.com, got %q", u.Hostname())
+	}
+	if u.Port() != 8080 || !u.HasExplicitPort() {
+		t.Errorf("Expected explicit port 8080, got %d", u.Port())
+	}
+	if u.Path() != "/a%2Fb/c" {
+		t.Errorf("Expected escaped path /a%%2Fb/c, got %q", u.Path())
+	}
+	if u.Query().Get("y") != "two" {
+		t.Errorf("Expected query y=two, got %q", u.RawQuery())
+	}
+	if u.Fragment() != "frag" {
+		t.Errorf("Expected fragment frag, got %q", u.Fragment())
+	}
+	if u.RequestURI() != "/a%2Fb/c?x=1&y=two" {
+		t.Errorf("Unexpected request URI %q", u.RequestURI())
+	}
+}
+
+func TestParse_DefaultPort(t *testing.T) {
+	u, err := Parse("https://example.com")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if u.Port() != 443 || u.HasExplicitPort() {
+		t.Errorf("Expected implicit port 443, got %d (explicit %v)", u.Port(), u.HasExplicitPort())
+	}
+}
+
+func TestParse_IDNHost(t *testing.T) {
+	u, err := Parse("http://Bücher.example/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if u.Hostname() != "xn--bcher-kva.example" {
+		t.Errorf("Expected punycode host, got %q", u.Hostname())
+	}
+	if u.String() != "http://xn--bcher-kva.example/" {
+		t.Errorf("Unexpected string form %q", u.String())
+	}
+}
+
+func TestParse_IPv6Host(t *testing.T) {
+	u, err := Parse("http://[2001:DB8::1]:8080/")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if u.Hostname() != "2001:db8::1" {
+		t.Errorf("Expected lowercased IPv6 host, got %q", u.Hostname())
+	}
+	if u.String() != "http://[2001:db8::1]:8080/" {
+		t.Errorf("Unexpected string form %q", u.String())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"/relative/path",
+		"mailto:someone@example.com",
+		"http://example.com:99999/",
+		"http://example.com:0/",
+		"http://exa mple.com/",
+		"http://" + strings.Repeat("a", 64) + ".com/",
+	} {
+		_, err := Parse(raw)
+		if err == nil {
+			t.Errorf("Expected error for %q", raw)
+			continue
+		}
+		httpErr, ok := err.(*httperrors.Error)
+		if !ok || httpErr.HttpErr == nil || *httpErr.HttpErr != httperrors.UrlParseFailure {
+			t.Errorf("Expected UrlParseFailure for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestURL_Normalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"HTTP://Example.COM:80", "http://example.com/"},
+		{"https://example.com:443/a/./b/../c", "https://example.com/a/c"},
+		{"http://example.com:8080/%7euser/%2f%e2%82%ac", "http://example.com:8080/~user/%2F%E2%82%AC"},
+		{"http://example.com/p?q=%41%3d", "http://example.com/p?q=A%3D"},
+		{"http://[::1]:80/", "http://[::1]/"},
+	}
+	for _, tt := range tests {
+		u, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.in, err)
+		}
+		if got := u.Normalize().String(); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestURL_Normalize_DoesNotModifyOriginal(t *testing.T) {
+	u, err := Parse("http://example.com:80/a/../b")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	u.Normalize()
+	if u.String() != "http://example.com:80/a/../b" {
+		t.Errorf("Original URL changed to %q", u.String())
+	}
+}
+
+func TestRemoveDotSegments(t *testing.T) {
+	// Examples from RFC 3986 section 5.2.4
+	tests := map[string]string{
+		"/a/b/c/./../../g":   "/a/g",
+		"mid/content=5/../6": "mid/6",
+		"/..":                "/",
+		"/a/..":              "/",
+		"../x":               "x",
+		"/a/b/..":            "/a/",
+	}
+	for in, want := range tests {
+		if got := removeDotSegments(in); got != want {
+			t.Errorf("removeDotSegments(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestURL_HostHeader(t *testing.T) {
+	tests := map[string]string{
+		"http://example.com/":           "example.com",
+		"http://example.com:80/":        "example.com",
+		"https://example.com:443/":      "example.com",
+		"http://example.com:443/":       "example.com:443",
+		"https://example.com:8443/":     "example.com:8443",
+		"http://[2001:db8::1]/":         "[2001:db8::1]",
+		"https://[2001:db8::1]:443/":    "[2001:db8::1]",
+		"http://[2001:db8::1]:8080/":    "[2001:db8::1]:8080",
+		"http://[fe80::1%25eth0]:8080/": "[fe80::1]:8080",
+		"http://[fe80::1%25eth0]/":      "[fe80::1]",
+		"http://Bücher.example:8080/":   "xn--bcher-kva.example:8080",
+		"http://user:pw@example.com/x":  "example.com",
+	}
+	for raw, want := range tests {
+		u, err := Parse(raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", raw, err)
+		}
+		if got := u.HostHeader(); got != want {
+			t.Errorf("HostHeader(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}